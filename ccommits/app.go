@@ -1,11 +1,16 @@
 package ccommits
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/display"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/lint"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/objects"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/styles"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
@@ -19,11 +24,17 @@ var DIRECTIONS map[tcell.Key]int = map[tcell.Key]int{
 
 type CCommitWindow struct {
 	screen   tcell.Screen            // The main screen of tcell
+	tb_scope *objects.TextBox        // The textbox for the scope of the change
 	tb_desc1 *objects.TextBox        // The textbox for the main description
 	tb_desc2 *objects.TextBox        // The textbox for the longer description
+	tg_break *objects.ToggleBox      // The toggle for flagging a breaking change
+	tb_break *objects.TextBox        // The textbox describing the breaking change
+	trb_foot *objects.TrailerBox     // The box with the footer trailers
 	mb_slct1 *objects.MultiOptionBox // The box for selecting the commit type
 	mb_slct2 *objects.MultiOptionBox // The box for selecting the gitmoji
 	gitinfo  *util.GitInfo           // Git Information of the current repo
+	config   *Config                 // The configuration of the current repo
+	objs     []objects.Object        // All the objects in focus order
 
 	size_w int // The total size in width of the screen
 	size_h int // The total size in height of the screen
@@ -33,17 +44,38 @@ type CCommitWindow struct {
 
 	prev_focus_obj objects.Object // Previously focused object
 	prev_focus_idx int            // Previous focused object index
+
+	rejected  string // The last message rejected because of lint errors
+	prefilled bool   // If the boxes have been filled from an existing message
+	body      string // The body the boxes have been filled with, kept unless edited
+	draft     string // The path where the draft is saved (empty if disabled)
+
+	placeholders []string // The placeholders of the applied template, if any
 }
 
-func CCommitWindow_new(gitinfo *util.GitInfo) *CCommitWindow {
+func CCommitWindow_new(gitinfo *util.GitInfo, config *Config) *CCommitWindow {
 	// First of all, let's create the screen of the main app
 	win := new(CCommitWindow)
 	win.screen = display.InitializeScreen()
 	win.size_w, win.size_h = win.screen.Size()
 
+	// Creates the textbox for the scope. Known scopes, i.e., those
+	// already used in the history, are proposed as completions, unless
+	// commitlint only allows some scopes
+	tbs_x := win.size_w/2 + 32
+	tbs_y := 9
+	tbs_size := win.size_w - 3 - tbs_x
+	win.tb_scope = objects.TextBox_new(SCOPE, tbs_x, tbs_y, tbs_size, 4)
+	scopes := config.GetScopes()
+	if len(scopes) < 1 {
+		scopes = util.GetKnownScopes(NOF_SCOPE_COMMITS)
+	}
+
+	win.tb_scope.SetCompletions(scopes)
+
 	// Creates the textbox for the main description
-	tbd1_x := win.size_w/2 + 32
-	tbd1_y := 9
+	tbd1_x := tbs_x
+	tbd1_y := tbs_y + 6
 	tbd1_size := win.size_w - 3 - tbd1_x
 	win.tb_desc1 = objects.TextBox_new(MAIN_DESC, tbd1_x, tbd1_y, tbd1_size, 5)
 
@@ -57,23 +89,61 @@ func CCommitWindow_new(gitinfo *util.GitInfo) *CCommitWindow {
 	// Creates the first Multi option selection box
 	mob1_x, mob1_y := 5, 9
 	mob1_size_w := win.size_w/4 - mob1_x + 8
-	mob1_size_h := win.size_h - 3 - mob1_y
+	mob1_size_h := (win.size_h - 3 - mob1_y) / 2
 	win.mb_slct1 = objects.MultiOptionBox_new(TYPE, mob1_x, mob1_y,
-		mob1_size_w, mob1_size_h, CHANGE_TYPE)
+		mob1_size_w, mob1_size_h, config.GetTypes())
+
+	// Creates the toggle for the breaking change, below the type box
+	tgb_x := mob1_x
+	tgb_y := mob1_y + mob1_size_h + 2
+	win.tg_break = objects.ToggleBox_new(BREAKING, BREAKING_LABEL, tgb_x, tgb_y, mob1_size_w)
+
+	// Creates the textbox describing the breaking change
+	tbb_x := mob1_x
+	tbb_y := tgb_y + 4
+	tbb_size_h := win.size_h - 3 - tbb_y
+	win.tb_break = objects.TextBox_new(BREAKING_DESC, tbb_x, tbb_y, mob1_size_w, tbb_size_h)
 
 	// Creates the second Multi option selection box
 	mob2_x, mob2_y := mob1_x+mob1_size_w+3, 9
 	mob2_size_w := tbd1_x - 3 - mob2_x
-	mob2_size_h := win.size_h - 3 - mob1_y
-	win.mb_slct2 = objects.MultiOptionBox_new(GITMOJI, mob2_x, mob2_y,
-		mob2_size_w, mob2_size_h, GITMOJI_ARRAY)
+	mob2_size_h := mob1_size_h
+	mob2_title := GITMOJI
+	if config.EmojiFormat == EMOJI_NONE {
+		mob2_title += GITMOJI_DISABLED
+	}
+
+	win.mb_slct2 = objects.MultiOptionBox_new(mob2_title, mob2_x, mob2_y,
+		mob2_size_w, mob2_size_h, GetGitmojiMap(config.GetGitmojis()))
+
+	// Creates the box for the footer trailers, below the gitmoji box
+	trb_x := mob2_x
+	trb_y := tgb_y
+	trb_size_h := win.size_h - 3 - trb_y
+	win.trb_foot = objects.TrailerBox_new(TRAILERS, trb_x, trb_y, mob2_size_w,
+		trb_size_h, TRAILER_KEYS)
 
 	// Sets the cursor position
 	win.cursor_x = 0
 	win.cursor_y = 0
 	win.prev_focus_obj = nil
 	win.prev_focus_idx = -1
+	win.rejected = ""
+	win.prefilled = false
+	win.body = ""
+	win.draft = ""
+	win.placeholders = nil
 	win.gitinfo = gitinfo
+	win.config = config
+	// Placeholders of the templates are highlighted until replaced
+	win.tb_scope.SetHighlight(win.screen, PLACEHOLDER_RE)
+	win.tb_desc1.SetHighlight(win.screen, PLACEHOLDER_RE)
+	win.tb_desc2.SetHighlight(win.screen, PLACEHOLDER_RE)
+	win.tb_desc2.SetMultiline(true)
+	win.tb_break.SetMultiline(true)
+
+	win.objs = []objects.Object{win.mb_slct1, win.mb_slct2, win.tb_scope,
+		win.tb_desc1, win.tb_desc2, win.tg_break, win.tb_break, win.trb_foot}
 
 	return win
 }
@@ -81,12 +151,11 @@ func CCommitWindow_new(gitinfo *util.GitInfo) *CCommitWindow {
 func (win *CCommitWindow) handleArrowPressed(key tcell.Key) {
 	direction := DIRECTIONS[key]
 	next_focus_idx := win.prev_focus_idx + direction
-	if next_focus_idx < 0 {
+	if next_focus_idx < 0 || next_focus_idx >= len(win.objs) {
 		return
 	}
 
-	objs := []objects.Object{win.mb_slct1, win.mb_slct2, win.tb_desc1, win.tb_desc2}
-	next_focus_obj := objs[next_focus_idx]
+	next_focus_obj := win.objs[next_focus_idx]
 	next_focus_obj.HandleEventMouse(win.screen, nil)
 	win.cursor_x, win.cursor_y = next_focus_obj.GetCursorPosition()
 	win.screen.Sync()
@@ -141,26 +210,41 @@ func (win *CCommitWindow) displayGitInfo() {
 	display.DrawString(win.screen, remote_name, remote_start_x, start_y, styles.GitInfoStyle)
 }
 
+// Displays a message in the status line, at the bottom of the screen
+func (win *CCommitWindow) displayStatus(status string) {
+	for col_idx := 0; col_idx < win.size_w; col_idx++ {
+		display.DrawString(win.screen, " ", col_idx, win.size_h-1, styles.SimpleStyle)
+	}
+
+	display.DrawString(win.screen, status, 5, win.size_h-1, styles.WarningStyle)
+}
+
 func (win *CCommitWindow) Display() {
 	// Display the title
 	win.displayTitle(TITLE)
 	win.displaySubTitle(VERSION)
 	win.displayGitInfo()
+	display.DrawString(win.screen, HISTORY_HINT, 5, TITLE_Y, styles.SubTitleStyle)
+	if len(win.config.Templates) > 0 {
+		display.DrawString(win.screen, TEMPLATES_HINT, 5, TITLE_Y+2, styles.SubTitleStyle)
+	}
 
 	// Draw the text boxes
+	win.tb_scope.Display(win.screen)
 	win.tb_desc1.Display(win.screen)
 	win.tb_desc2.Display(win.screen)
 	win.mb_slct1.Display(win.screen)
 	win.mb_slct2.Display(win.screen)
+	win.tg_break.Display(win.screen)
+	win.tb_break.Display(win.screen)
+	win.trb_foot.Display(win.screen)
 
 	// Show the screen
 	win.screen.Show()
 }
 
 func (win *CCommitWindow) getColliding(x, y int, focus bool) (int, objects.Object) {
-	objs := []objects.Object{win.mb_slct1, win.mb_slct2, win.tb_desc1, win.tb_desc2}
-
-	for idx, element := range objs {
+	for idx, element := range win.objs {
 		if element.IsColliding(x, y) && (!focus || (focus && element.HasFocus())) {
 			return idx, element
 		}
@@ -169,10 +253,261 @@ func (win *CCommitWindow) getColliding(x, y int, focus bool) (int, objects.Objec
 	return -1, nil
 }
 
+// Returns the commit message built from the content of all the boxes
+func (win *CCommitWindow) GetMessage() *message.Message {
+	msg := new(message.Message)
+	msg.Type = strings.ToLower(win.mb_slct1.GetContent())
+	msg.Scope = strings.TrimSpace(win.tb_scope.GetContent())
+	msg.Breaking = win.tg_break.IsChecked()
+	msg.Emoji = win.mb_slct2.GetContent()
+	msg.Subject = win.tb_desc1.GetContent()
+	msg.Body = win.tb_desc2.GetContent()
+
+	// The description of the breaking change goes into the footer
+	breaking_desc := strings.TrimSpace(win.tb_break.GetContent())
+	if msg.Breaking && len(breaking_desc) > 0 {
+		msg.AddTrailer(message.BREAKING_CHANGE, breaking_desc)
+	}
+
+	for _, row := range win.trb_foot.GetRows() {
+		msg.InsertTrailer(message.Trailer{Key: row.Key, Separator: row.Separator, Value: row.Value})
+	}
+
+	// A body loaded from an existing message is kept as it is, unless edited
+	win.config.ApplyEmojiStyle(msg)
+	if !win.prefilled || msg.Body != win.body {
+		win.config.ApplyBodyWrap(msg)
+	}
+
+	return msg
+}
+
+// Fills all the boxes with the content of the given message, e.g., the one
+// of the commit being amended
+func (win *CCommitWindow) Prefill(msg *message.Message) {
+	win.mb_slct1.Select(win.screen, strings.ToUpper(msg.Type))
+	if gitmoji, ok := FindGitmoji(win.config.GetGitmojis(), msg.Emoji); ok {
+		win.mb_slct2.Select(win.screen, gitmoji.Emoji)
+	}
+
+	win.tb_scope.SetContent(win.screen, msg.Scope)
+	win.tb_desc1.SetContent(win.screen, msg.Subject)
+	win.tb_desc2.SetContent(win.screen, msg.Body)
+	win.tg_break.SetChecked(win.screen, msg.Breaking)
+
+	// The description of the breaking change marked in the header has its
+	// own box, while all the other footers are rows of the trailers box
+	win.tb_break.SetContent(win.screen, "")
+	described := false
+	rows := make([]objects.KeyValue, 0, len(msg.Trailers))
+	for _, trailer := range msg.Trailers {
+		if msg.Breaking && !described && trailer.Key == message.BREAKING_CHANGE {
+			win.tb_break.SetContent(win.screen, trailer.Value)
+			described = true
+			continue
+		}
+
+		rows = append(rows, objects.KeyValue{Key: trailer.Key, Separator: trailer.Separator, Value: trailer.Value})
+	}
+
+	win.trb_foot.SetRows(win.screen, rows)
+	win.prefilled = true
+	win.body = msg.Body
+}
+
+// Returns an error if the boxes, as filled by Prefill, do not give back the
+// body and the footers of the message, e.g., when the footers are not in the
+// order the composer writes them. Such messages would be changed without
+// the user noticing, hence they must not replace existing ones.
+func (win *CCommitWindow) CheckPrefill(msg *message.Message) error {
+	composed := win.GetMessage()
+	if composed.Body != msg.Body {
+		return errors.New("the body cannot be loaded into the composer as it is")
+	}
+
+	if composed.Footer() != msg.Footer() {
+		return errors.New("the footers cannot be loaded into the composer as they are")
+	}
+
+	return nil
+}
+
+// Closes the composer without running it
+func (win *CCommitWindow) Close() {
+	win.screen.Fini()
+}
+
+// Fills the boxes with the template of the given name
+func (win *CCommitWindow) ApplyTemplate(name string) error {
+	template, ok := win.config.Templates[name]
+	if !ok {
+		return fmt.Errorf("unknown template %q", name)
+	}
+
+	win.Prefill(template.GetMessage())
+	if len(template.Emoji) < 1 {
+		win.suggestGitmoji()
+	}
+
+	win.placeholders = template.GetPlaceholders()
+	return nil
+}
+
+// Returns the first placeholder of the applied template not replaced yet
+func (win *CCommitWindow) getPlaceholder(commit_str string) string {
+	for _, placeholder := range win.placeholders {
+		if strings.Contains(commit_str, placeholder) {
+			return placeholder
+		}
+	}
+
+	return ""
+}
+
+// Lets the user pick one of the templates of the configuration, which
+// then fills the boxes
+func (win *CCommitWindow) pickTemplate() {
+	if len(win.config.Templates) < 1 {
+		return
+	}
+
+	// The templates are listed by name, along with their header
+	content := make(map[string]string, len(win.config.Templates))
+	for name, template := range win.config.Templates {
+		content[name] = template.String()
+	}
+
+	box_x, box_y := win.size_w/4, 9
+	box_size_h := min(len(content)+4, win.size_h-3-box_y)
+	box := objects.MultiOptionBox_new(TEMPLATES_TITLE, box_x, box_y, win.size_w/2, box_size_h, content)
+	box.Sort()
+
+	win.screen.Clear()
+	win.screen.HideCursor()
+	display.DrawString(win.screen, TEMPLATES_HELP, box_x, TITLE_Y, styles.SubTitleStyle)
+	box.SetFocus(true)
+	box.Display(win.screen)
+	win.screen.Show()
+
+	for {
+		switch ev := win.screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			switch ev.Key() {
+			case tcell.KeyEnter:
+				win.ApplyTemplate(box.GetContent())
+				win.redraw()
+				return
+
+			case tcell.KeyEscape, tcell.KeyF3:
+				win.redraw()
+				return
+
+			default:
+				box.HandleEventKey(win.screen, ev)
+				win.screen.HideCursor()
+				win.screen.Show()
+			}
+
+		case *tcell.EventResize:
+			win.screen.Sync()
+		}
+	}
+}
+
+// Enables saving the draft of the message into the given path, both
+// periodically and when the composer is closed
+func (win *CCommitWindow) SetDraft(path string) {
+	win.draft = path
+}
+
+// Saves the current content of the boxes into the draft, if enabled
+func (win *CCommitWindow) saveDraft() {
+	if len(win.draft) < 1 {
+		return
+	}
+
+	if err := SaveDraft(win.draft, win.GetMessage()); err != nil {
+		win.displayStatus(fmt.Sprintf("An Error occurred: %s", err))
+		win.screen.Show()
+	}
+}
+
+// Draws the whole composer, keeping the selected options in view
+func (win *CCommitWindow) redraw() {
+	win.screen.Clear()
+	win.Display()
+	win.mb_slct1.Select(win.screen, win.mb_slct1.GetContent())
+	win.mb_slct2.Select(win.screen, win.mb_slct2.GetContent())
+	win.updateHeaderLimits()
+	if win.prev_focus_obj != nil && win.prev_focus_obj.HasFocus() {
+		win.screen.ShowCursor(win.cursor_x, win.cursor_y)
+	}
+
+	win.screen.Show()
+}
+
+// Selects the gitmoji suggested for the currently selected type, if any
+func (win *CCommitWindow) suggestGitmoji() {
+	if win.config.EmojiFormat == EMOJI_NONE {
+		return
+	}
+
+	emoji, ok := win.config.GetTypeGitmojis()[win.mb_slct1.GetContent()]
+	if ok {
+		win.mb_slct2.Select(win.screen, emoji)
+	}
+}
+
+// Limits the short description to the length of the header left by the
+// type, the scope and the gitmoji
+func (win *CCommitWindow) updateHeaderLimits() {
+	msg := win.GetMessage()
+	msg.Subject = ""
+	header_len := utf8.RuneCountInString(msg.Header())
+	win.tb_desc1.SetLimits(win.screen, win.config.HeaderWarnLength-header_len,
+		win.config.HeaderMaxLength-header_len)
+}
+
+// Shows the recent commits of the current branch, then draws back the composer
+func (win *CCommitWindow) showHistory() {
+	commits, err := util.GetLastCommits(win.gitinfo.Curr_branch, NOF_HISTORY_COMMITS)
+	if err != nil {
+		win.displayStatus(fmt.Sprintf("An Error occurred: %s", err))
+		win.screen.Show()
+		return
+	}
+
+	entries := GetHistoryEntries(commits, win.config.GetLinter())
+	HistoryWindow_new(win.screen, HISTORY_TITLE, entries).Run()
+	win.redraw()
+}
+
 func (win *CCommitWindow) Run() string {
 	defer win.screen.Fini()
+	defer win.saveDraft()
+
+	// The draft is saved periodically by waking up the event loop
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(DRAFT_INTERVAL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				win.screen.PostEvent(tcell.NewEventInterrupt(nil))
+			}
+		}
+	}()
 
-	win.Display()
+	// A prefilled gitmoji is kept rather than suggested from the type
+	if !win.prefilled {
+		win.suggestGitmoji()
+	}
+
+	win.redraw()
 
 	// Wait for a key event
 	for {
@@ -180,20 +515,50 @@ func (win *CCommitWindow) Run() string {
 		switch ev := event.(type) {
 		case *tcell.EventKey:
 			if ev.Key() == tcell.KeyCtrlC {
-				// Fetch all the results
-				short_desc := win.tb_desc1.GetContent()
-				long_desc := win.tb_desc2.GetContent()
-				commit_type := strings.ToLower(win.mb_slct1.GetContent())
-				commit_emoji := win.mb_slct2.GetContent()
-
 				// Check that both descriptions have at least one char
-				if !(len(short_desc) > 1 && len(long_desc) > 1) {
+				msg := win.GetMessage()
+				if !(len(msg.Subject) > 1 && len(msg.Body) > 1) {
+					return ""
+				}
+
+				// Messages with lint errors or placeholders of the template are
+				// not returned, since they would be rejected by the hooks or the
+				// CI. If nothing changed since the last rejection, the composer
+				// is closed.
+				commit_str := msg.Format()
+				violations := win.config.GetLinter().Lint(commit_str)
+				placeholder := win.getPlaceholder(commit_str)
+				if !lint.HasErrors(violations) && len(placeholder) < 1 {
+					return commit_str
+				}
+
+				if commit_str == win.rejected {
 					return ""
 				}
 
-				// Otherwise, we can returns the formatted commit
-				return fmt.Sprintf("%s: %s %s\n\n%s", commit_type, commit_emoji,
-					short_desc, long_desc)
+				win.rejected = commit_str
+				status := fmt.Sprintf("The placeholder %s has not been replaced", placeholder)
+				for _, violation := range violations {
+					if len(placeholder) < 1 && violation.Severity == lint.ERROR {
+						status = fmt.Sprintf("[%s] %s", violation.Rule, violation.Message)
+						break
+					}
+				}
+
+				win.displayStatus(status + " (CTRL+C again to quit)")
+
+				win.screen.Show()
+				continue
+			}
+
+			if ev.Key() == tcell.KeyF2 {
+				win.showHistory()
+				continue
+			}
+
+			if ev.Key() == tcell.KeyF3 {
+				win.pickTemplate()
+				continue
 			}
 
 			_, obj := win.getColliding(win.cursor_x, win.cursor_y, true)
@@ -204,10 +569,19 @@ func (win *CCommitWindow) Run() string {
 				continue
 			}
 
+			prev_type := win.mb_slct1.GetContent()
 			obj.HandleEventKey(win.screen, ev) // Handle the event
+			if win.mb_slct1.GetContent() != prev_type {
+				win.suggestGitmoji() // A new type suggests a new gitmoji
+			}
+
+			win.updateHeaderLimits()
 			win.cursor_x, win.cursor_y = obj.GetCursorPosition()
 			win.screen.Sync()
 
+		case *tcell.EventInterrupt:
+			win.saveDraft()
+
 		case *tcell.EventMouse:
 			mouse_x, mouse_y := ev.Position()
 
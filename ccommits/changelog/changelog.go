@@ -0,0 +1,214 @@
+/*
+This package renders the conventional commits of the history as release
+notes, following the Keep a Changelog format.
+*/
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/lint"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+// The beginning of a new changelog file
+const HEADER string = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// The version of the changes not released yet
+const UNRELEASED string = "Unreleased"
+
+// The title of the section listing the breaking changes, always the first
+const BREAKING_SECTION string = "⚠ BREAKING CHANGES"
+
+type Section struct {
+	Type  string // The upper-case type of the commits in the section
+	Title string // The title of the section
+}
+
+// The sections of the known types, in the order they are rendered. Other
+// types follow in alphabetical order.
+var SECTIONS = []Section{
+	{"FEAT", "Features"},
+	{"FIX", "Bug Fixes"},
+	{"PERF", "Performance Improvements"},
+	{"REFACTOR", "Code Refactoring"},
+	{"DOCS", "Documentation"},
+	{"STYLE", "Styles"},
+	{"TEST", "Tests"},
+	{"BUILD", "Build System"},
+	{"OPS", "Operations"},
+	{"CI", "Continuous Integration"},
+	{"CHORE", "Chores"},
+	{"REVERT", "Reverts"},
+}
+
+type Entry struct {
+	Sha string           // The abbreviated hash of the commit
+	Msg *message.Message // The parsed message of the commit
+}
+
+// Returns the conventional commits among the given ones. Commits that do
+// not follow the format, or that are generated by git, are skipped.
+func GetEntries(commits []util.Commit) []Entry {
+	entries := make([]Entry, 0, len(commits))
+	for _, commit := range commits {
+		if lint.IsIgnored(commit.Message) {
+			continue
+		}
+
+		msg, err := message.Parse(commit.Message)
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, Entry{commit.GetShortSha(), msg})
+	}
+
+	return entries
+}
+
+// Returns the entry formatted as a list item, e.g., "- **api:** ✨ add (1a2b3c4)"
+func formatEntry(entry Entry, description string) string {
+	line := "- "
+	if len(entry.Msg.Scope) > 0 {
+		line += "**" + entry.Msg.Scope + ":** "
+	}
+
+	return fmt.Sprintf("%s%s (%s)", line, description, entry.Sha)
+}
+
+// Returns the title of the section of the given type
+func getSectionTitle(change_type string) string {
+	for _, section := range SECTIONS {
+		if section.Type == change_type {
+			return section.Title
+		}
+	}
+
+	return strings.ToUpper(change_type[:1]) + strings.ToLower(change_type[1:])
+}
+
+// Returns the types in the order their sections are rendered
+func getSectionOrder(groups map[string][]string) []string {
+	order := make([]string, 0, len(groups))
+	for _, section := range SECTIONS {
+		if _, ok := groups[section.Type]; ok {
+			order = append(order, section.Type)
+		}
+	}
+
+	others := make([]string, 0)
+	for change_type := range groups {
+		if !slices.Contains(order, change_type) {
+			others = append(others, change_type)
+		}
+	}
+
+	sort.Strings(others)
+	return append(order, others...)
+}
+
+// Renders the section of the changelog with the given version and date.
+// Breaking changes are listed first, then the commits grouped by type.
+func Render(version, date string, entries []Entry) string {
+	title := fmt.Sprintf("## [%s]", version)
+	if len(date) > 0 {
+		title += " - " + date
+	}
+
+	lines := []string{title}
+	breaking := make([]string, 0)
+	groups := make(map[string][]string)
+
+	for _, entry := range entries {
+		msg := entry.Msg
+		subject := strings.TrimSpace(msg.Emoji + " " + msg.Subject)
+		change_type := strings.ToUpper(msg.Type)
+		groups[change_type] = append(groups[change_type], formatEntry(entry, subject))
+
+		// The description of a breaking change is the footer, if any
+		descriptions := msg.GetBreakingChanges()
+		if len(descriptions) < 1 && msg.Breaking {
+			descriptions = []string{msg.Subject}
+		}
+
+		for _, description := range descriptions {
+			breaking = append(breaking, formatEntry(entry, description))
+		}
+	}
+
+	if len(breaking) > 0 {
+		lines = append(lines, "", "### "+BREAKING_SECTION, "")
+		lines = append(lines, breaking...)
+	}
+
+	for _, change_type := range getSectionOrder(groups) {
+		lines = append(lines, "", "### "+getSectionTitle(change_type), "")
+		lines = append(lines, groups[change_type]...)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Returns true if the heading is the one of the unreleased changes, i.e.,
+// ## [Unreleased] as in Keep a Changelog
+func isUnreleased(heading string) bool {
+	title := strings.Trim(strings.TrimPrefix(heading, "## "), " []")
+	return strings.EqualFold(title, "unreleased")
+}
+
+// Writes the section into the changelog file, before the previous versions
+// and after the section of the unreleased changes, if any, which is instead
+// replaced by a new section of the unreleased changes. The file is created
+// when it does not exist.
+func Prepend(path, section string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return os.WriteFile(path, []byte(HEADER+"\n"+section), 0644)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The section goes right before the first version
+	lines := strings.Split(string(data), "\n")
+	insert_idx, unreleased_idx := len(lines), -1
+	for idx, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+
+		if !isUnreleased(line) {
+			insert_idx = idx
+			break
+		}
+
+		if unreleased_idx < 0 {
+			unreleased_idx = idx
+		}
+	}
+
+	before, after := lines[:insert_idx], lines[insert_idx:]
+	if unreleased_idx >= 0 && isUnreleased(strings.SplitN(section, "\n", 2)[0]) {
+		before = lines[:unreleased_idx]
+	}
+
+	content := strings.TrimRight(strings.Join(before, "\n"), "\n") + "\n\n" + section
+	if len(after) > 0 {
+		content += "\n" + strings.Join(after, "\n")
+	}
+
+	return os.WriteFile(path, []byte(content), 0644)
+}
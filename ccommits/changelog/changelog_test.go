@@ -0,0 +1,137 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+var COMMITS = []util.Commit{
+	{Sha: "1111111aaaa", Date: "2024-05-04", Message: "feat(api): ✨ add the endpoint"},
+	{Sha: "2222222bbbb", Date: "2024-05-03", Message: "fix: close the file"},
+	{Sha: "3333333cccc", Date: "2024-05-02", Message: "update the readme"},
+	{Sha: "4444444dddd", Date: "2024-05-02", Message: "Merge branch 'dev'"},
+	{Sha: "5555555eeee", Date: "2024-05-01", Message: "feat!: new config\n\nBREAKING CHANGE: the format changed"},
+	{Sha: "6666666ffff", Date: "2024-05-01", Message: "deps: bump tcell"},
+	{Sha: "7777777aaaa", Date: "2024-04-30", Message: "refactor(ui)!: drop the old window"},
+}
+
+func TestGetEntries(t *testing.T) {
+	entries := GetEntries(COMMITS)
+	want := []string{"1111111", "2222222", "5555555", "6666666", "7777777"}
+	if len(entries) != len(want) {
+		t.Fatalf("GetEntries() returned %d entries, want %d", len(entries), len(want))
+	}
+
+	for idx, entry := range entries {
+		if entry.Sha != want[idx] {
+			t.Errorf("entry %d is %s, want %s", idx, entry.Sha, want[idx])
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	want := `## [v1.0.0] - 2024-05-04
+
+### ⚠ BREAKING CHANGES
+
+- the format changed (5555555)
+- **ui:** drop the old window (7777777)
+
+### Features
+
+- **api:** ✨ add the endpoint (1111111)
+- new config (5555555)
+
+### Bug Fixes
+
+- close the file (2222222)
+
+### Code Refactoring
+
+- **ui:** drop the old window (7777777)
+
+### Deps
+
+- bump tcell (6666666)
+`
+
+	if section := Render("v1.0.0", "2024-05-04", GetEntries(COMMITS)); section != want {
+		t.Errorf("Render() = %q, want %q", section, want)
+	}
+
+	if section := Render(UNRELEASED, "", nil); section != "## [Unreleased]\n" {
+		t.Errorf("Render() without entries = %q", section)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	first := "## [v1.0.0] - 2024-05-04\n\n### Features\n\n- a (1111111)\n"
+	second := "## [v1.1.0] - 2024-06-04\n\n### Bug Fixes\n\n- b (2222222)\n"
+
+	if err := Prepend(path, first); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Prepend(path, second); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := HEADER + "\n" + second + "\n" + first; string(data) != want {
+		t.Errorf("the changelog is %q, want %q", string(data), want)
+	}
+}
+
+func TestPrependAfterUnreleased(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	unreleased := "## [Unreleased]\n\n- c (3333333)\n"
+	first := "## [v1.0.0] - 2024-05-04\n\n### Features\n\n- a (1111111)\n"
+	second := "## [v1.1.0] - 2024-06-04\n\n### Bug Fixes\n\n- b (2222222)\n"
+	if err := os.WriteFile(path, []byte(HEADER+"\n"+unreleased+"\n"+first), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Prepend(path, second); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := HEADER + "\n" + unreleased + "\n" + second + "\n" + first; string(data) != want {
+		t.Errorf("the changelog is %q, want %q", string(data), want)
+	}
+}
+
+func TestPrependUnreleased(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	first := "## [v1.0.0] - 2024-05-04\n\n### Features\n\n- a (1111111)\n"
+	if err := os.WriteFile(path, []byte(HEADER+"\n## [Unreleased]\n\n- old (3333333)\n\n"+first), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The unreleased changes are replaced
+	unreleased := "## [Unreleased]\n\n- new (4444444)\n"
+	if err := Prepend(path, unreleased); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := HEADER + "\n" + unreleased + "\n" + first; string(data) != want {
+		t.Errorf("the changelog is %q, want %q", string(data), want)
+	}
+}
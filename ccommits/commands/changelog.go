@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/changelog"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+// Renders the changelog of the commits in the given range, e.g., v1.0.0..HEAD
+func Changelog(args []string) int {
+	flags := flag.NewFlagSet("changelog", flag.ExitOnError)
+	version := flags.String("version", changelog.UNRELEASED, "The version the changes belong to")
+	file := flags.String("file", "", "The changelog file the section is written into")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ccommits changelog [-version=<version>] [-file=<path>] [<from>..<to>]")
+		flags.PrintDefaults()
+	}
+
+	flags.Parse(args)
+
+	revision_range := "HEAD"
+	if flags.NArg() > 0 {
+		revision_range = flags.Arg(0)
+	}
+
+	commits, err := util.GetCommits(revision_range)
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	// Released versions are dated, as required by Keep a Changelog
+	date := ""
+	if *version != changelog.UNRELEASED {
+		date = time.Now().Format(time.DateOnly)
+	}
+
+	section := changelog.Render(*version, date, changelog.GetEntries(commits))
+	if len(*file) < 1 {
+		fmt.Print(section)
+		return 0
+	}
+
+	if err := changelog.Prepend(*file, section); err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	fmt.Printf("[*] Changelog written into %s\n", *file)
+	return 0
+}
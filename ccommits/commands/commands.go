@@ -0,0 +1,35 @@
+/*
+This package contains all the sub-commands of ccommits, i.e., everything that
+can be run as `ccommits <command> [args...]` instead of the commit composer.
+*/
+package commands
+
+import (
+	"os"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+// A command takes the command line arguments and returns the exit code
+type Command func(args []string) int
+
+var COMMANDS map[string]Command = map[string]Command{
+	"lint":         Lint,
+	"hooks":        Hooks,
+	"changelog":    Changelog,
+	"next-version": NextVersion,
+	"release":      Release,
+	"reword":       Reword,
+}
+
+// Returns the configuration of the repository containing the current folder
+func loadConfig() (*ccommits.Config, error) {
+	cwd, _ := os.Getwd()
+	rootpath, err := util.FindRepositoryRoot(cwd)
+	if err != nil {
+		return ccommits.Config_new(), nil
+	}
+
+	return ccommits.LoadConfig(rootpath)
+}
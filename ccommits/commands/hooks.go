@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/display"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+const HOOKS_USAGE string = "Usage: ccommits hooks install|uninstall|status"
+
+// Returns the hooks folder of the repository containing the current folder
+func getHooksDir() (string, error) {
+	cwd, _ := os.Getwd()
+	gitinfo := util.GetGitInfo(cwd)
+	if gitinfo == nil {
+		return "", fmt.Errorf("%s does not belong to a repository", cwd)
+	}
+
+	return gitinfo.GetHooksDir(), nil
+}
+
+// Returns a commented guide with the allowed types. Comments are removed
+// by git, hence the guide is only displayed in the editor.
+func getGuide(config *ccommits.Config) []string {
+	allowed := config.GetTypes()
+	types := make([]string, 0, len(allowed))
+	for change_type := range allowed {
+		types = append(types, change_type)
+	}
+
+	sort.Strings(types)
+	guide := []string{"# Write the message as: type(scope)!: <gitmoji> short description",
+		"#", "# Allowed types:"}
+	for _, change_type := range types {
+		line := fmt.Sprintf("#   %-10s %s", strings.ToLower(change_type), allowed[change_type])
+		line = strings.TrimRight(line, " ")
+		guide = append(guide, line)
+	}
+
+	return append(guide, "#")
+}
+
+// Opens the commit composer, filled with the given message if any, and
+// returns the composed message, or an empty string if it has not been
+// completed. No git command is run. The given message is refused if the
+// boxes cannot hold it exactly, since it would change without noticing.
+func composeMessage(config *ccommits.Config, prefill *message.Message) (string, error) {
+	cwd, _ := os.Getwd()
+	gitinfo := util.GetGitInfo(cwd)
+	if gitinfo == nil {
+		return "", nil
+	}
+
+	// The remote is only displayed, since nothing is pushed
+	gitinfo.Curr_remote = gitinfo.GetDefaultRemote()
+
+	win := ccommits.CCommitWindow_new(gitinfo, config)
+	if prefill != nil {
+		win.Prefill(prefill)
+		if err := win.CheckPrefill(prefill); err != nil {
+			win.Close()
+			return "", err
+		}
+	}
+
+	return win.Run(), nil
+}
+
+// Writes the message into the file that git passes to the hook. When a
+// terminal is available the message is composed with the commit composer,
+// otherwise (e.g., IDE commit buttons) a guide is added to the file.
+// Amended commits open the composer with their message, the file is left
+// as it is when it cannot be composed.
+func runPrepareCommitMsg(args []string) int {
+	// Nothing is done when the message is not written from scratch or
+	// taken from a commit, i.e., when a message, template, merge or squash
+	// is the source
+	source := ""
+	if len(args) > 1 {
+		source = args[1]
+	}
+
+	if len(args) < 1 || len(source) > 0 && source != "commit" {
+		return 0
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	// The message of the commit is placed by git before its comments
+	var prefill *message.Message
+	if source == "commit" {
+		prefill = ccommits.ParseCommitMessage(message.Cleanup(string(data)))
+	}
+
+	lines := strings.Split(string(data), "\n")
+	comment_idx := len(lines)
+	for idx, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			comment_idx = idx
+			break
+		}
+	}
+
+	composed := ""
+	if display.IsTerminalAvailable() {
+		composed, err = composeMessage(config, prefill)
+		if err != nil {
+			fmt.Printf("An Error occurred: %s, keeping the message as it is\n", err)
+			return 0
+		}
+	}
+
+	switch {
+	case len(composed) > 0:
+		// The composed message replaces the current one, while the
+		// comments written by git are kept below it
+		lines = append([]string{composed, ""}, lines[comment_idx:]...)
+	case prefill != nil:
+		// The message of the commit is kept when not composed
+		return 0
+	default:
+		// When the message has not been composed, the guide goes right
+		// before the comments written by git
+		lines = append(append(lines[:comment_idx:comment_idx], getGuide(config)...), lines[comment_idx:]...)
+	}
+
+	if err := os.WriteFile(args[0], []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// Lints all the commits that are going to be pushed. The refs to push are
+// read from the standard input, one per line, as written by git.
+func runPrePush(args []string) int {
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	linter := config.GetLinter()
+	failed := false
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		// Each line is: <local ref> <local sha> <remote ref> <remote sha>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || strings.Trim(fields[1], "0") == "" {
+			continue // Deleted refs have nothing to lint
+		}
+
+		commits, err := util.GetCommitsToPush(remote, fields[1], fields[3])
+		if err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+			return 1
+		}
+
+		for _, commit := range commits {
+			content, err := util.GetCommitMessage(commit)
+			if err != nil {
+				fmt.Printf("An Error occurred: %s\n", err)
+				return 1
+			}
+
+			violations := linter.Lint(content)
+			failed = ReportViolations(commit[:min(len(commit), 7)], violations) || failed
+		}
+	}
+
+	if failed {
+		fmt.Println("[*] Some commits do not follow the conventional commits format")
+		return 1
+	}
+
+	return 0
+}
+
+// Runs the ccommits side of the given hook
+func runHook(args []string) int {
+	if len(args) < 1 {
+		fmt.Println("Usage: ccommits hooks run <hook> [args...]")
+		return 2
+	}
+
+	switch args[0] {
+	case "commit-msg":
+		return Lint(append([]string{"-file"}, args[1:]...))
+	case "prepare-commit-msg":
+		return runPrepareCommitMsg(args[1:])
+	case "pre-push":
+		return runPrePush(args[1:])
+	default:
+		fmt.Printf("Unknown hook %s\n", args[0])
+		return 2
+	}
+}
+
+// Installs, uninstalls or shows the status of the ccommits git hooks
+func Hooks(args []string) int {
+	if len(args) < 1 {
+		fmt.Println(HOOKS_USAGE)
+		return 2
+	}
+
+	// Hooks run the command, hence no repository information is needed
+	if args[0] == "run" {
+		return runHook(args[1:])
+	}
+
+	hooks_dir, err := getHooksDir()
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "install":
+		executable, err := os.Executable()
+		if err == nil {
+			executable, err = filepath.EvalSymlinks(executable)
+		}
+
+		if err == nil {
+			err = util.InstallHooks(hooks_dir, executable)
+		}
+
+		if err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+			return 1
+		}
+
+		fmt.Printf("[*] Hooks installed into %s\n", hooks_dir)
+
+	case "uninstall":
+		if err := util.UninstallHooks(hooks_dir); err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+			return 1
+		}
+
+		fmt.Printf("[*] Hooks removed from %s\n", hooks_dir)
+
+	case "status":
+		fmt.Printf("HOOKS FOLDER: %s\n", hooks_dir)
+		for _, hook := range util.HOOKS {
+			fmt.Printf("   %-20s %s\n", hook, util.GetHookStatus(hooks_dir, hook))
+		}
+
+	default:
+		fmt.Println(HOOKS_USAGE)
+		return 2
+	}
+
+	return 0
+}
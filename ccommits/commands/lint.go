@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/lint"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+// Reads the message to lint from the given file, commit or the standard input
+func readLintInput(file, commit string) (string, error) {
+	if len(commit) > 0 {
+		return util.GetCommitMessage(commit)
+	}
+
+	if len(file) > 0 && file != "-" {
+		// Files might be the ones written by git, containing comments
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		return message.Cleanup(string(data)), nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Prints all the violations and returns true if at least one is an error
+func ReportViolations(source string, violations []lint.Violation) bool {
+	nof_errors := 0
+	for _, violation := range violations {
+		fmt.Printf("%s: %s\n", source, violation)
+		if violation.Severity == lint.ERROR {
+			nof_errors++
+		}
+	}
+
+	if len(violations) > 0 {
+		fmt.Printf("%s: found %d problems, %d errors and %d warnings\n", source,
+			len(violations), nof_errors, len(violations)-nof_errors)
+	}
+
+	return nof_errors > 0
+}
+
+// Validates a commit message read from a file, a commit or the standard input
+func Lint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	file := flags.String("file", "", "The file containing the message (- for stdin)")
+	commit := flags.String("commit", "", "The commit whose message is linted")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ccommits lint [-file=<path>] [-commit=<sha>]")
+		flags.PrintDefaults()
+	}
+
+	flags.Parse(args)
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 2
+	}
+
+	content, err := readLintInput(*file, *commit)
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 2
+	}
+
+	// The source is displayed as prefix of each violation
+	source := "stdin"
+	if len(*commit) > 0 {
+		source = *commit
+	} else if len(*file) > 0 {
+		source = *file
+	}
+
+	if ReportViolations(source, config.GetLinter().Lint(content)) {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/release"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+// Computes the next version from the commits made since the latest version,
+// which are returned as well
+func getNextVersion(rules map[string]release.Bump) (release.NextVersion, []util.Commit, error) {
+	tags, err := util.GetTags("HEAD")
+	if err != nil {
+		return release.NextVersion{}, nil, err
+	}
+
+	// Without any version, the whole history is considered
+	current, tag := release.GetLatestVersion(tags)
+	revision_range := "HEAD"
+	if len(tag) > 0 {
+		revision_range = tag + "..HEAD"
+	}
+
+	commits, err := util.GetCommits(revision_range)
+	if err != nil {
+		return release.NextVersion{}, nil, err
+	}
+
+	return release.GetNextVersion(current, tag, commits, rules), commits, nil
+}
+
+// Prints the version following the latest one reachable from HEAD
+func NextVersion(args []string) int {
+	flags := flag.NewFlagSet("next-version", flag.ExitOnError)
+	json_flag := flags.Bool("json", false, "Print the versions and the commits causing the bump as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ccommits next-version [-json]")
+		flags.PrintDefaults()
+	}
+
+	flags.Parse(args)
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	next, _, err := getNextVersion(config.GetBumpRules())
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	if !*json_flag {
+		fmt.Println(next.Next)
+		return 0
+	}
+
+	data, err := json.MarshalIndent(next, "", "  ")
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	fmt.Println(string(data))
+	return 0
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/changelog"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+// A step of the release, only described when running dry
+type releaseStep struct {
+	description string       // What the step does
+	run         func() error // Performs the step
+}
+
+// Releases the next version: the changelog is updated and committed, then
+// the commit is tagged with the release notes and pushed with the tag.
+func Release(args []string) int {
+	flags := flag.NewFlagSet("release", flag.ExitOnError)
+	dry_run := flags.Bool("dry-run", false, "Print every step without changing anything")
+	file := flags.String("file", "CHANGELOG.md", "The changelog file, relative to the repository root")
+	remote := flags.String("remote", "", "The remote the release is pushed into (asked if there are more than one)")
+	yes_flag := flags.Bool("yes", false, "Skip the confirmation before pushing")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ccommits release [-dry-run] [-file=<path>] [-remote=<name>] [-yes]")
+		flags.PrintDefaults()
+	}
+
+	flags.Parse(args)
+
+	cwd, _ := os.Getwd()
+	gitinfo := util.GetGitInfo(cwd)
+	if gitinfo == nil {
+		return 1
+	}
+
+	// The release commit is pushed into the current branch, hence nothing
+	// is done when there is none, instead of stopping halfway
+	if len(gitinfo.Curr_branch) < 1 {
+		fmt.Println("An Error occurred: the HEAD is detached, checkout the branch to release")
+		return 1
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	// Nothing is pushed with -dry-run, hence the remote is never asked
+	if err := gitinfo.SelectRemote(*remote, !*yes_flag && !*dry_run); err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	next, commits, err := getNextVersion(config.GetBumpRules())
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	if len(next.Commits) < 1 {
+		fmt.Printf("[*] No commits since %s increase the version, nothing to release\n", next.Current)
+		return 0
+	}
+
+	// The release notes are the changelog section of the version
+	version := next.Next.String()
+	notes := changelog.Render(version, time.Now().Format(time.DateOnly), changelog.GetEntries(commits))
+	changelog_path := filepath.Join(gitinfo.TargetPath, *file)
+
+	// The header is always chore(release): vX.Y.Z, whatever the gitmoji style
+	msg := &message.Message{Type: "chore", Scope: "release", Subject: version,
+		Body: fmt.Sprintf("Release %s, see %s for the release notes.", version, *file)}
+	commit_str := msg.Format()
+
+	steps := []releaseStep{
+		{fmt.Sprintf("Write the release notes into %s:\n\n%s", *file, notes), func() error {
+			return changelog.Prepend(changelog_path, notes)
+		}},
+		{fmt.Sprintf("Commit %s with message %q", *file, msg.Header()), func() error {
+			return util.CommitFiles(commit_str, changelog_path)
+		}},
+		{fmt.Sprintf("Create the annotated tag %s with the release notes", version), func() error {
+			return util.CreateTag(version, notes)
+		}},
+		{fmt.Sprintf("Push %s and %s into %s", gitinfo.Curr_branch, version, gitinfo.Curr_remote), func() error {
+			return gitinfo.Push(*yes_flag, gitinfo.Curr_branch, version)
+		}},
+	}
+
+	fmt.Printf("[*] Releasing %s (%s bump from %s)\n", version, next.Bump, next.Current)
+	for idx, step := range steps {
+		fmt.Printf("[%d/%d] %s\n", idx+1, len(steps), step.description)
+		if *dry_run {
+			continue
+		}
+
+		if err := step.run(); err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+// The environment passing the commit and its new message to the editors
+// run by git during the rebase
+const REWORD_COMMIT_ENV string = "CCOMMITS_REWORD_COMMIT"
+const REWORD_MESSAGE_ENV string = "CCOMMITS_REWORD_MESSAGE"
+
+// Marks the given commit to be reworded in the todo list of the rebase
+func editTodoList(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	commit := os.Getenv(REWORD_COMMIT_ENV)
+	lines := strings.Split(string(data), "\n")
+	for idx, line := range lines {
+		// The command might be abbreviated, i.e., with rebase.abbreviateCommands
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "pick" && fields[0] != "p" || !strings.HasPrefix(commit, fields[1]) {
+			continue
+		}
+
+		lines[idx] = "reword" + line[strings.Index(line, fields[0])+len(fields[0]):]
+		return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+	}
+
+	return fmt.Errorf("commit %s not found in the rebase todo list", commit)
+}
+
+// Runs the editors of the rebase, i.e., the sequence editor receiving the
+// todo list and the editor receiving the message of the reworded commit
+func runEditor(args []string) int {
+	if len(args) < 2 {
+		fmt.Println("Usage: ccommits reword editor todo|message <file>")
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "todo":
+		err = editTodoList(args[1])
+	case "message":
+		err = os.WriteFile(args[1], []byte(os.Getenv(REWORD_MESSAGE_ENV)+"\n"), 0644)
+	default:
+		fmt.Printf("Unknown editor %s\n", args[0])
+		return 2
+	}
+
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// Opens the composer filled with the message of the given commit, then
+// replaces the message with an interactive rebase. Git runs ccommits itself
+// as editor, which marks the commit as reworded and writes the new message.
+func Reword(args []string) int {
+	// The editors are run by git, hence no repository information is needed
+	if len(args) > 0 && args[0] == "editor" {
+		return runEditor(args[1:])
+	}
+
+	flags := flag.NewFlagSet("reword", flag.ExitOnError)
+	force := flags.Bool("force", false, "Reword the commit even if it has already been pushed")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ccommits reword [-force] <commit>")
+		flags.PrintDefaults()
+	}
+
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	cwd, _ := os.Getwd()
+	if util.GetGitInfo(cwd) == nil {
+		return 1
+	}
+
+	commit, err := util.ResolveCommit(flags.Arg(0))
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	if !util.IsAncestor(commit, "HEAD") {
+		fmt.Printf("An Error occurred: %s is not in the history of the current branch\n", flags.Arg(0))
+		return 1
+	}
+
+	// Rewriting pushed commits forces everyone else to rewrite theirs
+	branches, err := util.GetRemoteBranches(commit)
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	if len(branches) > 0 && !*force {
+		fmt.Printf("An Error occurred: %s has already been pushed into %s (use -force to reword it anyway)\n",
+			flags.Arg(0), strings.Join(branches, ", "))
+		return 1
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	msg, err := ccommits.LoadCommitMessage(commit)
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	commit_str, err := composeMessage(config, msg)
+	if err != nil {
+		fmt.Printf("An Error occurred: %s, reword it with git rebase -i\n", err)
+		return 1
+	}
+
+	if len(commit_str) < 1 {
+		fmt.Println("Invalid formatted conventional commit. Exiting ...")
+		return 1
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	// The editors are run by git through the shell
+	editor := fmt.Sprintf("'%s' reword editor", strings.ReplaceAll(executable, "'", `'\''`))
+	env := []string{
+		fmt.Sprintf("GIT_SEQUENCE_EDITOR=%s todo", editor),
+		fmt.Sprintf("GIT_EDITOR=%s message", editor),
+		fmt.Sprintf("%s=%s", REWORD_COMMIT_ENV, commit),
+		fmt.Sprintf("%s=%s", REWORD_MESSAGE_ENV, commit_str),
+	}
+
+	// The message is committed as composed, while the default cleanup
+	// would drop the lines starting with #, e.g., Markdown headings
+	env = append(env, util.GetConfigEnv("commit.cleanup", "verbatim")...)
+
+	base := commit + "^"
+	if util.IsRootCommit(commit) {
+		base = ""
+	}
+
+	fmt.Printf("[*] Rewording %s\n", flags.Arg(0))
+	if err := util.Rebase(base, env); err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
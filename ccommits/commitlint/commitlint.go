@@ -0,0 +1,198 @@
+/*
+This package reads the commitlint configuration of the repository, so that
+messages composed and linted by ccommits follow the same rules used in CI.
+*/
+package commitlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The files looked for at the root of the repository, in order. Only the
+// JSON formats are supported.
+var CONFIG_FILES = []string{".commitlintrc.json", ".commitlintrc", "package.json"}
+
+// When a rule applies, as in commitlint
+const ALWAYS string = "always"
+const NEVER string = "never"
+
+// The level of a rule, as in commitlint
+const LEVEL_DISABLED int = 0
+const LEVEL_WARNING int = 1
+const LEVEL_ERROR int = 2
+
+type Rule struct {
+	Level      int             // Either disabled, warning or error
+	Applicable string          // Either always or never
+	Value      json.RawMessage // The value of the rule, if any
+}
+
+type Config struct {
+	Source  string          `json:"-"`       // The file the configuration is read from
+	Extends []string        `json:"extends"` // The shared configurations extended
+	Rules   map[string]Rule `json:"rules"`   // The rules, by name
+}
+
+// Decodes a rule from the commitlint format, i.e., [level, applicable, value]
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || len(fields) < 1 {
+		return fmt.Errorf("rules must be written as [level, applicable, value]")
+	}
+
+	r.Applicable = ALWAYS
+	if err := json.Unmarshal(fields[0], &r.Level); err != nil {
+		return fmt.Errorf("the level of a rule must be 0, 1 or 2")
+	}
+
+	if len(fields) > 1 {
+		if err := json.Unmarshal(fields[1], &r.Applicable); err != nil ||
+			(r.Applicable != ALWAYS && r.Applicable != NEVER) {
+			return fmt.Errorf("rules must apply either %s or %s", ALWAYS, NEVER)
+		}
+	}
+
+	if len(fields) > 2 {
+		r.Value = fields[2]
+	}
+
+	return nil
+}
+
+// Returns true if the rule is enabled
+func (r Rule) IsEnabled() bool {
+	return r.Level > LEVEL_DISABLED
+}
+
+// Returns the value of the rule as a list of strings. A single
+// string is returned as a list with one element.
+func (r Rule) GetStrings() []string {
+	var values []string
+	if err := json.Unmarshal(r.Value, &values); err == nil {
+		return values
+	}
+
+	var value string
+	if err := json.Unmarshal(r.Value, &value); err == nil {
+		return []string{value}
+	}
+
+	return nil
+}
+
+// Returns the value of the rule as a number, or -1 if it is not a number
+func (r Rule) GetInt() int {
+	value := -1
+	if err := json.Unmarshal(r.Value, &value); err != nil {
+		return -1
+	}
+
+	return value
+}
+
+// Reads the configuration from the given file. The configuration of a
+// package.json file is the one under the commitlint key.
+func readConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := new(Config)
+	if filepath.Base(path) == "package.json" {
+		var package_json struct {
+			Commitlint *Config `json:"commitlint"`
+		}
+
+		if err := json.Unmarshal(data, &package_json); err != nil {
+			return nil, err
+		}
+
+		config = package_json.Commitlint
+	} else if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Loads the commitlint configuration from the root of the repository. It
+// returns nil if the repository does not use commitlint. Rules of known
+// shared configurations are added, while those in the file take precedence.
+func Load(rootpath string) (*Config, error) {
+	for _, filename := range CONFIG_FILES {
+		path := filepath.Join(rootpath, filename)
+		config, err := readConfig(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", filename, err)
+		}
+
+		if config == nil {
+			continue // A package.json without commitlint configuration
+		}
+
+		config.Source = filename
+		if config.Rules == nil {
+			config.Rules = make(map[string]Rule)
+		}
+
+		for _, extended := range config.Extends {
+			for name, rule := range PRESETS[extended] {
+				if _, ok := config.Rules[name]; !ok {
+					config.Rules[name] = rule
+				}
+			}
+		}
+
+		return config, nil
+	}
+
+	return nil, nil
+}
+
+// Returns the rule with the given name, if it is enabled
+func (c *Config) GetRule(name string) (Rule, bool) {
+	if c == nil {
+		return Rule{}, false
+	}
+
+	rule, ok := c.Rules[name]
+	return rule, ok && rule.IsEnabled()
+}
+
+// Returns the values that must be used by the given enum rule, if any
+func (c *Config) GetEnum(name string) []string {
+	rule, ok := c.GetRule(name)
+	if !ok || rule.Applicable != ALWAYS {
+		return nil
+	}
+
+	return rule.GetStrings()
+}
+
+// Returns the values that must not be used by the given enum rule, if any
+func (c *Config) GetForbidden(name string) []string {
+	rule, ok := c.GetRule(name)
+	if !ok || rule.Applicable != NEVER {
+		return nil
+	}
+
+	return rule.GetStrings()
+}
+
+// Returns the limit of the given length rule, or -1 if there is none
+func (c *Config) GetMaxLength(name string) int {
+	rule, ok := c.GetRule(name)
+	if !ok || rule.Applicable != ALWAYS {
+		return -1
+	}
+
+	return rule.GetInt()
+}
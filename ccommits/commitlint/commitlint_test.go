@@ -0,0 +1,156 @@
+package commitlint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+)
+
+// Writes the given files into a new folder and returns its path
+func writeFiles(t *testing.T, files map[string]string) string {
+	rootpath := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(rootpath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return rootpath
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		files   map[string]string
+		source  string
+		enum    []string
+		max_len int
+	}{
+		{map[string]string{}, "", nil, -1},
+		{map[string]string{"package.json": `{"name": "app"}`}, "", nil, -1},
+		{map[string]string{".commitlintrc.json": `{"rules": {"type-enum": [2, "always", ["feat", "fix"]]}}`},
+			".commitlintrc.json", []string{"feat", "fix"}, -1},
+		{map[string]string{".commitlintrc": `{"rules": {"header-max-length": [1, "always", 50]}}`},
+			".commitlintrc", nil, 50},
+		{map[string]string{"package.json": `{"commitlint": {"rules": {"type-enum": [2, "always", "feat"]}}}`},
+			"package.json", []string{"feat"}, -1},
+		// The files are looked for in order, the first one found is used
+		{map[string]string{
+			".commitlintrc.json": `{"rules": {"header-max-length": [2, "always", 60]}}`,
+			".commitlintrc":      `{"rules": {"header-max-length": [2, "always", 70]}}`},
+			".commitlintrc.json", nil, 60},
+		// The rules of the file take precedence over the extended ones
+		{map[string]string{".commitlintrc.json": `{"extends": ["@commitlint/config-conventional"],
+			"rules": {"header-max-length": [2, "always", 72], "type-enum": [0]}}`},
+			".commitlintrc.json", nil, 72},
+		{map[string]string{".commitlintrc.json": `{"extends": ["@commitlint/config-conventional"]}`},
+			".commitlintrc.json", PRESETS["@commitlint/config-conventional"]["type-enum"].GetStrings(), 100},
+		// Disabled rules and never rules do not give any value
+		{map[string]string{".commitlintrc.json": `{"rules": {"type-enum": [2, "never", ["wip"]],
+			"header-max-length": [0, "always", 50]}}`}, ".commitlintrc.json", nil, -1},
+	}
+
+	for idx, test := range tests {
+		config, err := Load(writeFiles(t, test.files))
+		if err != nil {
+			t.Errorf("test %d: Load failed: %s", idx, err)
+			continue
+		}
+
+		source := ""
+		if config != nil {
+			source = config.Source
+		}
+
+		if source != test.source {
+			t.Errorf("test %d: Source = %q, want %q", idx, source, test.source)
+		}
+
+		if enum := config.GetEnum("type-enum"); !reflect.DeepEqual(enum, test.enum) {
+			t.Errorf("test %d: GetEnum() = %v, want %v", idx, enum, test.enum)
+		}
+
+		if max_len := config.GetMaxLength("header-max-length"); max_len != test.max_len {
+			t.Errorf("test %d: GetMaxLength() = %d, want %d", idx, max_len, test.max_len)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	invalid := []string{
+		`{"rules": {"type-enum": 2}}`,
+		`{"rules": {"type-enum": []}}`,
+		`{"rules": {"type-enum": ["error"]}}`,
+		`{"rules": {"type-enum": [2, "sometimes"]}}`,
+		`{"rules": `,
+	}
+
+	for _, content := range invalid {
+		if _, err := Load(writeFiles(t, map[string]string{".commitlintrc.json": content})); err == nil {
+			t.Errorf("Load(%s) succeeded, want an error", content)
+		}
+	}
+}
+
+func TestGetForbidden(t *testing.T) {
+	config, err := Load(writeFiles(t, map[string]string{
+		".commitlintrc.json": `{"rules": {"scope-enum": [2, "never", ["wip", "tmp"]]}}`}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if forbidden := config.GetForbidden("scope-enum"); !reflect.DeepEqual(forbidden, []string{"wip", "tmp"}) {
+		t.Errorf("GetForbidden() = %v", forbidden)
+	}
+}
+
+func TestGetLintRules(t *testing.T) {
+	config, err := Load(writeFiles(t, map[string]string{".commitlintrc.json": `{"rules": {
+		"header-max-length": [2, "always", 30],
+		"subject-case": [2, "never", ["sentence-case", "upper-case"]],
+		"subject-full-stop": [1, "never", "."],
+		"scope-enum": [2, "always", ["api", "ui"]],
+		"scope-empty": [2, "never"],
+		"body-max-line-length": [2, "always", 10],
+		"footer-leading-blank": [1, "always"],
+		"references-empty": [2, "never"]}}`}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"feat(api): add the endpoint", []string{}},
+		{"feat(api,ui): add the endpoint", []string{}},
+		{"feat(api): Add the endpoint.", []string{"subject-case", "subject-full-stop"}},
+		{"feat(api): add `Endpoint`", []string{}},
+		{"feat(db): add the endpoint", []string{"scope-enum"}},
+		{"feat: add the endpoint", []string{"scope-empty"}},
+		{"feat(api): add the endpoint to the server", []string{"header-max-length"}},
+		{"feat(api): add the endpoint\n\nshort\nlines", []string{}},
+		{"feat(api): add the endpoint\n\na line too long", []string{"body-max-line-length"}},
+	}
+
+	rules := config.GetLintRules()
+	for _, test := range tests {
+		msg, err := message.Parse(test.content)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		violated := make([]string, 0)
+		for _, rule := range rules {
+			if len(rule.Check(msg)) > 0 {
+				violated = append(violated, rule.Id)
+			}
+		}
+
+		if !reflect.DeepEqual(violated, test.want) {
+			t.Errorf("%q violates %v, want %v", test.content, violated, test.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package commitlint
+
+import "encoding/json"
+
+// Returns a rule written as in the commitlint configuration
+func rule(level int, applicable string, value string) Rule {
+	if len(value) < 1 {
+		return Rule{level, applicable, nil}
+	}
+
+	return Rule{level, applicable, json.RawMessage(value)}
+}
+
+// The rules of the shared configurations that can be extended. Since they
+// are node packages, their rules are replicated here.
+var PRESETS = map[string]map[string]Rule{
+	"@commitlint/config-conventional": {
+		"body-leading-blank":     rule(LEVEL_WARNING, ALWAYS, ""),
+		"body-max-line-length":   rule(LEVEL_ERROR, ALWAYS, "100"),
+		"footer-leading-blank":   rule(LEVEL_WARNING, ALWAYS, ""),
+		"footer-max-line-length": rule(LEVEL_ERROR, ALWAYS, "100"),
+		"header-max-length":      rule(LEVEL_ERROR, ALWAYS, "100"),
+		"header-trim":            rule(LEVEL_ERROR, ALWAYS, ""),
+		"subject-case": rule(LEVEL_ERROR, NEVER,
+			`["sentence-case", "start-case", "pascal-case", "upper-case"]`),
+		"subject-empty":     rule(LEVEL_ERROR, NEVER, ""),
+		"subject-full-stop": rule(LEVEL_ERROR, NEVER, `"."`),
+		"type-case":         rule(LEVEL_ERROR, ALWAYS, `"lower-case"`),
+		"type-empty":        rule(LEVEL_ERROR, NEVER, ""),
+		"type-enum": rule(LEVEL_ERROR, ALWAYS,
+			`["build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"]`),
+	},
+}
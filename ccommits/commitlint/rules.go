@@ -0,0 +1,298 @@
+package commitlint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/lint"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+)
+
+// Quoted content, ignored when checking the case since it may contain names
+var QUOTED_RE = regexp.MustCompile("`.*?`|\".*?\"|'.*?'")
+
+// The parts of the message the rules refer to
+var FIELDS = []string{"type", "scope", "subject", "header", "body", "footer"}
+
+// The delimiters between multiple scopes, e.g., feat(api,ui)
+var SCOPE_DELIMITERS = "/\\,"
+
+// A condition of a rule, returning true when satisfied by the value
+type condition func(value string, rule Rule) bool
+
+// Returns the part of the message with the given name as commitlint sees
+// it. The subject contains the gitmoji, when written after the colon, while
+// a gitmoji before the type prevents commitlint from parsing the header.
+func getField(msg *message.Message, field string) string {
+	unparsable := len(msg.Emoji) > 0 && msg.Prefix
+	switch field {
+	case "type", "scope", "subject":
+		if unparsable {
+			return ""
+		}
+	}
+
+	switch field {
+	case "type":
+		return msg.Type
+	case "scope":
+		return msg.Scope
+	case "subject":
+		if len(msg.Emoji) > 0 && !msg.Prefix {
+			return msg.Emoji + " " + msg.Subject
+		}
+
+		return msg.Subject
+	case "header":
+		return msg.Header()
+	case "body":
+		return msg.Body
+	default:
+		return msg.Footer()
+	}
+}
+
+// Returns the length of the string as computed by commitlint, i.e., in
+// UTF-16 code units, hence some emoji count as two characters
+func getLength(value string) int {
+	return len(utf16.Encode([]rune(value)))
+}
+
+// Splits the value into words, both at separators and case changes
+func getWords(value string) []string {
+	words := make([]string, 0)
+	curr_word := []rune{}
+	runes := []rune(value)
+
+	for idx, char := range runes {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+			if len(curr_word) > 0 {
+				words = append(words, string(curr_word))
+			}
+
+			curr_word = []rune{}
+			continue
+		}
+
+		// A new word starts at aB and at the B of ABc
+		if len(curr_word) > 0 && unicode.IsUpper(char) {
+			prev := curr_word[len(curr_word)-1]
+			next_lower := idx+1 < len(runes) && unicode.IsLower(runes[idx+1])
+			if unicode.IsLower(prev) || unicode.IsUpper(prev) && next_lower {
+				words = append(words, string(curr_word))
+				curr_word = []rune{}
+			}
+		}
+
+		curr_word = append(curr_word, char)
+	}
+
+	if len(curr_word) > 0 {
+		words = append(words, string(curr_word))
+	}
+
+	return words
+}
+
+// Returns the word with the first letter in upper-case
+func upperFirst(word string) string {
+	runes := []rune(word)
+	if len(runes) < 1 {
+		return word
+	}
+
+	return string(unicode.ToUpper(runes[0])) + string(runes[1:])
+}
+
+// Converts the value into the given case, as done by commitlint
+func toCase(value, target string) string {
+	words := getWords(value)
+	switch target {
+	case "lower-case", "lowercase":
+		return strings.ToLower(value)
+	case "upper-case", "uppercase":
+		return strings.ToUpper(value)
+	case "sentence-case", "sentencecase":
+		return upperFirst(value)
+	case "start-case":
+		for idx := range words {
+			words[idx] = upperFirst(words[idx])
+		}
+
+		return strings.Join(words, " ")
+	case "pascal-case", "camel-case":
+		for idx := range words {
+			words[idx] = upperFirst(strings.ToLower(words[idx]))
+		}
+
+		converted := strings.Join(words, "")
+		if target == "camel-case" && len(converted) > 0 {
+			runes := []rune(converted)
+			converted = string(unicode.ToLower(runes[0])) + string(runes[1:])
+		}
+
+		return converted
+	case "kebab-case", "snake-case":
+		separator := map[string]string{"kebab-case": "-", "snake-case": "_"}[target]
+		return strings.ToLower(strings.Join(words, separator))
+	default:
+		return value
+	}
+}
+
+// Returns true if the value is written in the given case. Quoted content
+// is ignored, as done by commitlint.
+func isCase(value, target string) bool {
+	value = strings.TrimSpace(QUOTED_RE.ReplaceAllString(value, ""))
+	converted := toCase(value, target)
+	if len(converted) < 1 || unicode.IsDigit([]rune(converted)[0]) {
+		return true
+	}
+
+	return converted == value
+}
+
+// The conditions of the rules, by the suffix of the rule name
+var CONDITIONS = map[string]condition{
+	"enum": func(value string, rule Rule) bool {
+		for _, part := range strings.FieldsFunc(value, func(char rune) bool {
+			return strings.ContainsRune(SCOPE_DELIMITERS, char)
+		}) {
+			found := false
+			for _, allowed := range rule.GetStrings() {
+				found = found || strings.TrimSpace(part) == allowed
+			}
+
+			if !found {
+				return false
+			}
+		}
+
+		return true
+	},
+	"case": func(value string, rule Rule) bool {
+		for _, target := range rule.GetStrings() {
+			if isCase(value, target) {
+				return true
+			}
+		}
+
+		return false
+	},
+	"empty": func(value string, rule Rule) bool {
+		return len(strings.TrimSpace(value)) < 1
+	},
+	"max-length": func(value string, rule Rule) bool {
+		return getLength(value) <= rule.GetInt()
+	},
+	"min-length": func(value string, rule Rule) bool {
+		return getLength(value) >= rule.GetInt()
+	},
+	"full-stop": func(value string, rule Rule) bool {
+		stops := rule.GetStrings()
+		return len(stops) > 0 && strings.HasSuffix(value, stops[0])
+	},
+	"max-line-length": func(value string, rule Rule) bool {
+		for _, line := range strings.Split(value, "\n") {
+			if getLength(line) > rule.GetInt() {
+				return false
+			}
+		}
+
+		return true
+	},
+	"leading-blank": func(value string, rule Rule) bool {
+		return true // The message model always separates the paragraphs
+	},
+	"trim": func(value string, rule Rule) bool {
+		return value == strings.TrimSpace(value)
+	},
+}
+
+// Returns what the rule requires, used to describe violations
+func describe(suffix string, rule Rule) string {
+	switch suffix {
+	case "enum":
+		return fmt.Sprintf("be one of [%s]", strings.Join(rule.GetStrings(), ", "))
+	case "case":
+		return "be " + strings.Join(rule.GetStrings(), ", ")
+	case "empty":
+		return "be empty"
+	case "max-length":
+		return fmt.Sprintf("be at most %d characters long", rule.GetInt())
+	case "min-length":
+		return fmt.Sprintf("be at least %d characters long", rule.GetInt())
+	case "full-stop":
+		return fmt.Sprintf("end with %q", strings.Join(rule.GetStrings(), ""))
+	case "max-line-length":
+		return fmt.Sprintf("have lines at most %d characters long", rule.GetInt())
+	case "leading-blank":
+		return "begin with a blank line"
+	default:
+		return "have leading or trailing whitespaces removed"
+	}
+}
+
+// Converts a commitlint rule into a lint rule. Returns false if the rule
+// is not supported.
+func getLintRule(name string, rule Rule) (lint.Rule, bool) {
+	field, suffix, _ := strings.Cut(name, "-")
+	check, ok := CONDITIONS[suffix]
+	if !ok || !slices.Contains(FIELDS, field) {
+		return lint.Rule{}, false
+	}
+
+	severity := lint.WARNING
+	if rule.Level >= LEVEL_ERROR {
+		severity = lint.ERROR
+	}
+
+	return lint.Rule{Id: name, Severity: severity, Check: func(msg *message.Message) []string {
+		value := getField(msg, field)
+
+		// Like in commitlint, only the empty rules check empty values
+		if len(value) < 1 && suffix != "empty" && suffix != "leading-blank" {
+			return nil
+		}
+
+		if check(value, rule) == (rule.Applicable == ALWAYS) {
+			return nil
+		}
+
+		negation := ""
+		if rule.Applicable == NEVER {
+			negation = "not "
+		}
+
+		return []string{fmt.Sprintf("%s must %s%s", field, negation, describe(suffix, rule))}
+	}}, true
+}
+
+// Returns the enabled and supported rules as lint rules, sorted by name
+func (c *Config) GetLintRules() []lint.Rule {
+	if c == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(c.Rules))
+	for name, rule := range c.Rules {
+		if rule.IsEnabled() {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	rules := make([]lint.Rule, 0, len(names))
+	for _, name := range names {
+		if rule, ok := getLintRule(name, c.Rules[name]); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
@@ -0,0 +1,380 @@
+package ccommits
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/commitlint"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/lint"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/objects"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/release"
+)
+
+// The configuration file, placed at the root of the repository
+const CONFIG_FILE string = ".ccommits.json"
+
+// How the gitmoji is written into the header
+const EMOJI_UNICODE string = "unicode"
+const EMOJI_SHORTCODE string = "shortcode"
+const EMOJI_NONE string = "none"
+
+// Where the gitmoji is placed into the header
+const EMOJI_AFTER_COLON string = "after-colon"
+const EMOJI_PREFIX string = "prefix"
+
+// How the gitmoji of the configuration are combined with the catalogue
+const GITMOJI_EXTEND string = "extend"
+const GITMOJI_REPLACE string = "replace"
+
+// The default limits of the length of the header
+const HEADER_WARN_LENGTH int = 72
+const HEADER_MAX_LENGTH int = 100
+
+// The default width at which the body is wrapped
+const BODY_WRAP_LENGTH int = 72
+
+// The name of a type, since it is written into the header
+var TYPE_NAME_RE = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+type Config struct {
+	EmojiFormat   string            `json:"emoji_format"`   // Either unicode, shortcode or none
+	EmojiPosition string            `json:"emoji_position"` // Either after-colon or prefix
+	Types         map[string]string `json:"types"`          // The types with their description
+	DisabledTypes []string          `json:"disabled_types"` // The types that are not allowed
+	Gitmojis      []Gitmoji         `json:"gitmojis"`       // Custom or overridden gitmoji
+	GitmojiMode   string            `json:"gitmoji_mode"`   // Either extend or replace
+	TypeGitmojis  map[string]string `json:"type_gitmojis"`  // The gitmoji suggested for each type
+
+	HeaderWarnLength int `json:"header_warn_length"` // The header should not be longer
+	HeaderMaxLength  int `json:"header_max_length"`  // The header must not be longer
+	BodyWrapLength   int `json:"body_wrap_length"`   // The body is wrapped at this width (0 to disable)
+
+	BumpRules map[string]string   `json:"bump_rules"` // How much each type increases the version
+	Templates map[string]Template `json:"templates"`  // The templates prefilling the composer
+
+	Commitlint *commitlint.Config `json:"-"` // The commitlint configuration, if any
+}
+
+// Returns the default configuration
+func Config_new() *Config {
+	config := new(Config)
+	config.EmojiFormat = EMOJI_UNICODE
+	config.EmojiPosition = EMOJI_AFTER_COLON
+	config.GitmojiMode = GITMOJI_EXTEND
+	config.HeaderWarnLength = HEADER_WARN_LENGTH
+	config.HeaderMaxLength = HEADER_MAX_LENGTH
+	config.BodyWrapLength = BODY_WRAP_LENGTH
+	return config
+}
+
+// Checks that all the values of the configuration are valid
+func (c *Config) validate() error {
+	switch c.EmojiFormat {
+	case EMOJI_UNICODE, EMOJI_SHORTCODE, EMOJI_NONE:
+	default:
+		return fmt.Errorf("emoji_format must be one of %s, %s or %s",
+			EMOJI_UNICODE, EMOJI_SHORTCODE, EMOJI_NONE)
+	}
+
+	switch c.EmojiPosition {
+	case EMOJI_AFTER_COLON, EMOJI_PREFIX:
+	default:
+		return fmt.Errorf("emoji_position must be either %s or %s",
+			EMOJI_AFTER_COLON, EMOJI_PREFIX)
+	}
+
+	switch c.GitmojiMode {
+	case GITMOJI_EXTEND, GITMOJI_REPLACE:
+	default:
+		return fmt.Errorf("gitmoji_mode must be either %s or %s",
+			GITMOJI_EXTEND, GITMOJI_REPLACE)
+	}
+
+	for _, gitmoji := range c.Gitmojis {
+		if err := gitmoji.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.GitmojiMode == GITMOJI_REPLACE && len(c.Gitmojis) < 1 {
+		return fmt.Errorf("gitmojis cannot be empty when gitmoji_mode is %s", GITMOJI_REPLACE)
+	}
+
+	if c.HeaderWarnLength < 1 || c.HeaderMaxLength < c.HeaderWarnLength {
+		return fmt.Errorf("header_warn_length must be positive and at most header_max_length")
+	}
+
+	if c.BodyWrapLength < 0 {
+		return fmt.Errorf("body_wrap_length cannot be negative")
+	}
+
+	for _, bump := range c.BumpRules {
+		if _, err := release.ParseBump(bump); err != nil {
+			return err
+		}
+	}
+
+	gitmojis := c.GetGitmojis()
+	for change_type, emoji := range c.TypeGitmojis {
+		if _, ok := FindGitmoji(gitmojis, emoji); len(emoji) > 0 && !ok {
+			return fmt.Errorf("gitmoji %q of type %q is not a known gitmoji", emoji, change_type)
+		}
+	}
+
+	for change_type := range c.Types {
+		if !TYPE_NAME_RE.MatchString(change_type) {
+			return fmt.Errorf("type %q is not a valid type name", change_type)
+		}
+	}
+
+	if len(c.GetTypes()) < 1 {
+		return fmt.Errorf("at least one type must be allowed")
+	}
+
+	for name, template := range c.Templates {
+		if err := template.validate(name, c.GetTypes(), gitmojis); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the allowed types, keyed by their upper-case name. The types of
+// the configuration replace the built-in ones, then disabled types are removed.
+func (c *Config) GetTypes() map[string]string {
+	types := CHANGE_TYPE
+	if len(c.Types) > 0 {
+		types = c.Types
+	}
+
+	allowed := make(map[string]string, len(types))
+	for change_type, description := range types {
+		allowed[strings.ToUpper(change_type)] = description
+	}
+
+	// The types of commitlint replace the others, keeping the descriptions
+	if enum := c.Commitlint.GetEnum("type-enum"); len(enum) > 0 {
+		described := allowed
+		allowed = make(map[string]string, len(enum))
+		for _, change_type := range enum {
+			description, ok := described[strings.ToUpper(change_type)]
+			if !ok {
+				description = CHANGE_TYPE[strings.ToUpper(change_type)]
+			}
+
+			allowed[strings.ToUpper(change_type)] = description
+		}
+	}
+
+	disabled := append(append([]string{}, c.DisabledTypes...), c.Commitlint.GetForbidden("type-enum")...)
+	for _, change_type := range disabled {
+		delete(allowed, strings.ToUpper(change_type))
+	}
+
+	return allowed
+}
+
+// Returns the allowed gitmoji. The gitmoji of the configuration either
+// replace the catalogue or extend it, overriding those with the same
+// emoji or shortcode.
+func (c *Config) GetGitmojis() []Gitmoji {
+	if c.GitmojiMode == GITMOJI_REPLACE {
+		return c.Gitmojis
+	}
+
+	gitmojis := make([]Gitmoji, 0, len(GITMOJI_CATALOGUE)+len(c.Gitmojis))
+	for _, gitmoji := range GITMOJI_CATALOGUE {
+		if _, ok := FindGitmoji(c.Gitmojis, gitmoji.Emoji); ok {
+			continue
+		}
+
+		if _, ok := FindGitmoji(c.Gitmojis, gitmoji.Code); ok {
+			continue
+		}
+
+		gitmojis = append(gitmojis, gitmoji)
+	}
+
+	return append(gitmojis, c.Gitmojis...)
+}
+
+// Returns the scopes allowed by commitlint, or nil if any scope is allowed
+func (c *Config) GetScopes() []string {
+	return c.Commitlint.GetEnum("scope-enum")
+}
+
+// Returns how much each type increases the version, keyed by the upper-case
+// type. The configuration overrides the defaults.
+func (c *Config) GetBumpRules() map[string]release.Bump {
+	rules := make(map[string]release.Bump, len(TYPE_BUMP))
+	for _, bump_rules := range []map[string]string{TYPE_BUMP, c.BumpRules} {
+		for change_type, name := range bump_rules {
+			bump, _ := release.ParseBump(name) // Already validated
+			rules[strings.ToUpper(change_type)] = bump
+		}
+	}
+
+	return rules
+}
+
+// Returns the gitmoji suggested for each type, keyed by the upper-case
+// type. The configuration overrides the defaults, while an empty gitmoji
+// removes the suggestion. Shortcodes are converted into the emoji.
+func (c *Config) GetTypeGitmojis() map[string]string {
+	suggestions := make(map[string]string, len(TYPE_GITMOJI))
+	for change_type, emoji := range TYPE_GITMOJI {
+		suggestions[change_type] = emoji
+	}
+
+	for change_type, emoji := range c.TypeGitmojis {
+		suggestions[strings.ToUpper(change_type)] = emoji
+	}
+
+	gitmojis := c.GetGitmojis()
+	for change_type, emoji := range suggestions {
+		gitmoji, ok := FindGitmoji(gitmojis, emoji)
+		if !ok {
+			delete(suggestions, change_type)
+			continue
+		}
+
+		suggestions[change_type] = gitmoji.Emoji
+	}
+
+	return suggestions
+}
+
+// Loads the commitlint configuration, whose limits tighten those of ccommits
+func (c *Config) loadCommitlint(rootpath string) error {
+	config, err := commitlint.Load(rootpath)
+	if err != nil {
+		return err
+	}
+
+	c.Commitlint = config
+	if max_length := config.GetMaxLength("header-max-length"); max_length > 0 {
+		c.HeaderMaxLength = min(c.HeaderMaxLength, max_length)
+		c.HeaderWarnLength = min(c.HeaderWarnLength, max_length)
+	}
+
+	max_length := config.GetMaxLength("body-max-line-length")
+	if max_length > 0 && (c.BodyWrapLength < 1 || c.BodyWrapLength > max_length) {
+		c.BodyWrapLength = max_length
+	}
+
+	return nil
+}
+
+// Returns the linter with the rules implied by the commit composer. The
+// rules of commitlint replace the built-in ones with the same name.
+func (c *Config) GetLinter() *lint.Linter {
+	linter := lint.Linter_new(c.GetTypes(), GetGitmojiMap(c.GetGitmojis()), c.GetTypeGitmojis())
+	linter.Rules = append(linter.Rules,
+		lint.HeaderLengthRule_new("header-max-length", lint.ERROR, c.HeaderMaxLength))
+	if c.HeaderWarnLength < c.HeaderMaxLength {
+		linter.Rules = append(linter.Rules,
+			lint.HeaderLengthRule_new("header-warn-length", lint.WARNING, c.HeaderWarnLength))
+	}
+
+	// When gitmoji are disabled, they must not be written at all
+	if c.EmojiFormat == EMOJI_NONE {
+		linter.SetSeverity("gitmoji-empty", lint.DISABLED)
+		linter.SetSeverity("gitmoji-enum", lint.DISABLED)
+		linter.SetSeverity("gitmoji-type", lint.DISABLED)
+		linter.SetSeverity("gitmoji-forbidden", lint.ERROR)
+	}
+
+	for _, rule := range c.Commitlint.GetLintRules() {
+		linter.SetRule(rule)
+	}
+
+	return linter
+}
+
+// Loads the configuration file from the root of the repository. Values
+// missing from the file, or the whole file, fall back to the defaults.
+func LoadConfig(rootpath string) (*Config, error) {
+	config := Config_new()
+	data, err := os.ReadFile(filepath.Join(rootpath, CONFIG_FILE))
+	if os.IsNotExist(err) {
+		return config, config.loadCommitlint(rootpath)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", CONFIG_FILE, err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", CONFIG_FILE, err)
+	}
+
+	return config, config.loadCommitlint(rootpath)
+}
+
+// Returns the shortcode of the given gitmoji, e.g., :sparkles: for ✨
+func (c *Config) GetGitmojiShortcode(emoji string) string {
+	gitmoji, ok := FindGitmoji(c.GetGitmojis(), emoji)
+	if !ok {
+		return emoji
+	}
+
+	return gitmoji.Code
+}
+
+// Writes the gitmoji of the message as requested by the configuration
+func (c *Config) ApplyEmojiStyle(msg *message.Message) {
+	switch c.EmojiFormat {
+	case EMOJI_SHORTCODE:
+		msg.Emoji = c.GetGitmojiShortcode(msg.Emoji)
+	case EMOJI_NONE:
+		msg.Emoji = ""
+	}
+
+	msg.Prefix = c.EmojiPosition == EMOJI_PREFIX
+}
+
+// Wraps the body of the message at the width requested by the configuration
+func (c *Config) ApplyBodyWrap(msg *message.Message) {
+	msg.Body = message.Wrap(msg.Body, c.BodyWrapLength)
+}
+
+// Formats a message not written with the composer, e.g., given on the
+// command line, as the composer would do. Without a gitmoji, the one
+// suggested for the type is used. Returns the formatted message along with
+// the problems found by the linter.
+func (c *Config) FormatMessage(msg *message.Message) (string, []lint.Violation, error) {
+	change_type := strings.ToUpper(msg.Type)
+	if _, ok := c.GetTypes()[change_type]; !ok {
+		return "", nil, fmt.Errorf("type %q is not an allowed type", msg.Type)
+	}
+
+	if len(msg.Emoji) < 1 {
+		msg.Emoji = c.GetTypeGitmojis()[change_type]
+	} else if gitmoji, ok := FindGitmoji(c.GetGitmojis(), msg.Emoji); ok {
+		msg.Emoji = gitmoji.Emoji
+	} else {
+		return "", nil, fmt.Errorf("gitmoji %q is not a known gitmoji", msg.Emoji)
+	}
+
+	for _, trailer := range msg.Trailers {
+		if !objects.TRAILER_KEY_RE.MatchString(trailer.Key) && !message.IsBreakingToken(trailer.Key) {
+			return "", nil, fmt.Errorf("%q is not a valid trailer key", trailer.Key)
+		}
+	}
+
+	msg.Type = strings.ToLower(msg.Type)
+	c.ApplyEmojiStyle(msg)
+	c.ApplyBodyWrap(msg)
+	commit_str := msg.Format()
+	return commit_str, c.GetLinter().Lint(commit_str), nil
+}
@@ -13,61 +13,59 @@ var CHANGE_TYPE = map[string]string{
 	"CHORE":    "Miscellaneous commits",
 }
 
-var GITMOJI_ARRAY = map[string]string{
-	"✨":  ":sparkles: Introduce new features",
-	"🎨":  ":art: Improve structure of the code",
-	"⚡️": ":zap: Improve performance",
-	"🔥":  ":fire: Remove code or files",
-	"🐛":  ":bug: Fix a bug",
-	"🚑️": ":ambulance: Critical hotfix",
-	"📝":  ":memo: Add or update documentation",
-	"🚀":  ":rocket: Deply stuff",
-	"🎉":  ":tada: Begin a project",
-	"✅":  ":white_check_mark: Add, update, or pass tests",
-	"🔒️": ":lock: Fix security or privacy issue",
-	"🔐":  ":closed_lock_with_key: Add or update secrets",
-	"🔖":  ":bookmark: Release/Versions tags",
-	"🚨":  ":rotating_light: Fix compiler/linter warnings",
-	"🚧":  ":construction: Work in progress",
-	"💚":  ":green_heart: Fix CI Build",
-	"⬇️": ":arrow_down: Downgrade dependencies",
-	"⬆️": ":arrow_up: Upgrade dependencies",
-	"📌":  ":pushpin: Pin dependencies to specific version",
-	"👷":  ":construction_worker: Add or update CI system",
-	"♻️": ":recycle: Refactor code",
-	"➕":  ":heavy_plus_sign: Add a dependency",
-	"➖":  ":heavy_minus_sign: Remove a dependency",
-	"🔧":  ":wrench: Add or update configuration files",
-	"🔨":  ":hammer: Add or update development scripts",
-	"✏️": ":pencil2: Fix typos",
-	"💩":  ":poop: Write bad code that needs to be improved",
-	"⏪️": ":rewind: Revert changes",
-	"🔀":  ":twisted_rightwards_arrows: Merge branches",
-	"📦️": ":package: Add or update compiled packages",
-	"👽️": ":alien: Update code due to external API changes",
-	"🚚":  ":truck: Move or renamed resources (paths, ...)",
-	"💥":  ":boom: Introduce breaking changes",
-	"🍱":  ":bento: Add or update assets",
-	"🔊":  ":loud_sound: Add or update logs",
-	"🔇":  ":mute: Remove logs",
-	"🏗️": ":building_construction: Make architectural changes",
-	"🤡":  ":clown_face: Mock things",
-	"🙈":  ":see_no_evil: Add or update a .gitignore file",
-	"⚰️": ":coffin: Remove dead code",
-	"🧪":  ":test_tube: Add a failing test",
-	"🧱":  ":bricks: Infrastructure releated changes",
-	"🦺":  ":safety_vest: Add or update code for validation",
+// The gitmoji suggested for each type
+var TYPE_GITMOJI = map[string]string{
+	"FEAT":     "✨",
+	"FIX":      "🐛",
+	"REFACTOR": "♻️",
+	"PERF":     "⚡️",
+	"STYLE":    "🎨",
+	"TEST":     "✅",
+	"DOCS":     "📝",
+	"BUILD":    "📦️",
+	"OPS":      "🧱",
+	"CHORE":    "🔧",
+}
+
+// How much each type increases the version, the others do not
+var TYPE_BUMP = map[string]string{
+	"FEAT": "minor",
+	"FIX":  "patch",
+	"PERF": "patch",
+}
+
+// Known keys of the footer trailers
+var TRAILER_KEYS = []string{
+	"Closes",
+	"Refs",
+	"Reviewed-by",
+	"Co-authored-by",
+	"Signed-off-by",
 }
 
 // String constants
 const TITLE string = "CONVENTIONAL COMMITS CLI"
 const TYPE string = "1. Select the type of change"
 const GITMOJI string = "2. Select a gitmoji"
-const MAIN_DESC string = "3. Write a Short Description"
-const LONG_DESC string = "4. Write a Longer Description"
+const GITMOJI_DISABLED string = " (disabled by the configuration)"
+const SCOPE string = "3. Write a Scope (TAB to complete)"
+const MAIN_DESC string = "4. Write a Short Description"
+const LONG_DESC string = "5. Write a Longer Description"
+const BREAKING string = "6. Breaking Change"
+const BREAKING_LABEL string = "This commit introduces a BREAKING CHANGE"
+const BREAKING_DESC string = "7. Describe the Breaking Change"
+const TRAILERS string = "8. Footer Trailers (TAB key, ENTER add, DEL remove)"
 const VERSION string = "v0.1.0 - Riccardo La Marca"
 const REPO string = "📦"
 const BRANCH string = "🌲"
 const REMOTE string = "👾"
+const HISTORY_HINT string = "F2 History"
+const HISTORY_TITLE string = "Recent Commits"
+const HISTORY_HELP string = "UP/DOWN select a commit, ESC or F2 go back"
+const TEMPLATES_HINT string = "F3 Templates"
+const TEMPLATES_TITLE string = "Templates"
+const TEMPLATES_HELP string = "UP/DOWN select a template, ENTER apply it, ESC or F3 go back"
 
 const TITLE_Y int = 2
+const NOF_SCOPE_COMMITS int = 500
+const NOF_HISTORY_COMMITS int = 200
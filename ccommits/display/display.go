@@ -2,6 +2,8 @@ package display
 
 import (
 	"log"
+	"os"
+	"runtime"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -30,6 +32,22 @@ func InitializeScreen() tcell.Screen {
 	return screen
 }
 
+// Returns true if a terminal is available for displaying the screen, which
+// is not the case, e.g., for git hooks run by IDEs
+func IsTerminalAvailable() bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+
+	tty.Close()
+	return true
+}
+
 func DrawString(screen tcell.Screen, content string, start_x, start_y int, style tcell.Style) {
 	prev_width := 0
 	for _, char := range content {
@@ -0,0 +1,72 @@
+package ccommits
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+// The draft of the message being composed, placed into the git folder
+const DRAFT_FILE string = "CCOMMITS_DRAFT.json"
+
+// How often the draft is saved while composing
+const DRAFT_INTERVAL time.Duration = 5 * time.Second
+
+// Returns the path of the draft of the current worktree
+func GetDraftPath(gitinfo *util.GitInfo) string {
+	return filepath.Join(gitinfo.BranchDir, DRAFT_FILE)
+}
+
+// Returns true if nothing has been written into the message
+func isEmptyDraft(msg *message.Message) bool {
+	return len(msg.Scope) < 1 && len(msg.Subject) < 1 && len(msg.Body) < 1 &&
+		len(msg.Trailers) < 1 && !msg.Breaking
+}
+
+// Writes the message into the draft. Empty messages remove the draft.
+func SaveDraft(path string, msg *message.Message) error {
+	if isEmptyDraft(msg) {
+		return RemoveDraft(path)
+	}
+
+	data, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// Returns the message saved into the draft, or nil if there is no draft
+func LoadDraft(path string) (*message.Message, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	msg := new(message.Message)
+	if err := json.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// Removes the draft, if any
+func RemoveDraft(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
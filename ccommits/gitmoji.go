@@ -0,0 +1,90 @@
+package ccommits
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The full gitmoji catalogue, see https://gitmoji.dev
+//
+//go:embed data/gitmojis.json
+var GITMOJI_DATA []byte
+
+// The semantic version level implied by a gitmoji
+const SEMVER_MAJOR string = "major"
+const SEMVER_MINOR string = "minor"
+const SEMVER_PATCH string = "patch"
+
+type Gitmoji struct {
+	Emoji       string `json:"emoji"`       // The unicode emoji
+	Code        string `json:"code"`        // The shortcode, e.g., :sparkles:
+	Description string `json:"description"` // What the gitmoji is used for
+	Semver      string `json:"semver"`      // Either major, minor, patch or empty
+}
+
+// All the gitmoji shipped with ccommits
+var GITMOJI_CATALOGUE []Gitmoji = loadGitmojiCatalogue()
+
+// Decodes the embedded catalogue. It is part of the binary, hence any error
+// is a programming error.
+func loadGitmojiCatalogue() []Gitmoji {
+	var catalogue struct {
+		Gitmojis []Gitmoji `json:"gitmojis"`
+	}
+
+	if err := json.Unmarshal(GITMOJI_DATA, &catalogue); err != nil {
+		panic(fmt.Sprintf("invalid gitmoji catalogue: %s", err))
+	}
+
+	return catalogue.Gitmojis
+}
+
+// Checks that the gitmoji can be written and parsed back
+func (g Gitmoji) validate() error {
+	if len(g.Emoji) < 1 || strings.ContainsAny(g.Emoji, " \t\n") {
+		return fmt.Errorf("gitmoji %q must be a single emoji", g.Emoji)
+	}
+
+	if len(g.Code) < 3 || !strings.HasPrefix(g.Code, ":") || !strings.HasSuffix(g.Code, ":") {
+		return fmt.Errorf("gitmoji code %q must be written as :code:", g.Code)
+	}
+
+	switch g.Semver {
+	case "", SEMVER_MAJOR, SEMVER_MINOR, SEMVER_PATCH:
+	default:
+		return fmt.Errorf("gitmoji semver %q must be one of %s, %s or %s",
+			g.Semver, SEMVER_MAJOR, SEMVER_MINOR, SEMVER_PATCH)
+	}
+
+	return nil
+}
+
+// Returns the gitmoji as displayed by the UI, i.e., ":code: description"
+func (g Gitmoji) String() string {
+	return g.Code + " " + g.Description
+}
+
+// Returns the gitmoji with the given emoji or shortcode, if any
+func FindGitmoji(gitmojis []Gitmoji, emoji string) (Gitmoji, bool) {
+	emoji = strings.ReplaceAll(emoji, "\ufe0f", "")
+	for _, gitmoji := range gitmojis {
+		if strings.ReplaceAll(gitmoji.Emoji, "\ufe0f", "") == emoji || gitmoji.Code == emoji {
+			return gitmoji, true
+		}
+	}
+
+	return Gitmoji{}, false
+}
+
+// Returns the gitmoji keyed by emoji, with the shortcode and the description
+// as value, which is the format used by the UI and the linter
+func GetGitmojiMap(gitmojis []Gitmoji) map[string]string {
+	content := make(map[string]string, len(gitmojis))
+	for _, gitmoji := range gitmojis {
+		content[gitmoji.Emoji] = gitmoji.String()
+	}
+
+	return content
+}
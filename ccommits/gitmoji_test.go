@@ -0,0 +1,23 @@
+package ccommits
+
+import "testing"
+
+func TestGitmojiCatalogue(t *testing.T) {
+	// The catalogue mirrors the one of https://gitmoji.dev
+	if len(GITMOJI_CATALOGUE) != 75 {
+		t.Errorf("the catalogue has %d gitmojis, want 75", len(GITMOJI_CATALOGUE))
+	}
+
+	codes := make(map[string]bool)
+	for _, gitmoji := range GITMOJI_CATALOGUE {
+		if codes[gitmoji.Code] {
+			t.Errorf("gitmoji %s is listed more than once", gitmoji.Code)
+		}
+
+		codes[gitmoji.Code] = true
+	}
+
+	if gitmoji, ok := FindGitmoji(GITMOJI_CATALOGUE, ":t-rex:"); !ok || gitmoji.Emoji != "🦖" {
+		t.Errorf("FindGitmoji(\":t-rex:\") = %v, %t, want 🦖", gitmoji, ok)
+	}
+}
@@ -0,0 +1,247 @@
+package ccommits
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/display"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/lint"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/objects"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/styles"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+	"github.com/mattn/go-runewidth"
+)
+
+// The width of the columns of the history, the subject takes the rest
+var HISTORY_COLUMNS = []int{9, 12, 16, 9}
+
+type HistoryEntry struct {
+	Commit     util.Commit      // The commit as listed by git
+	Msg        *message.Message // The parsed message (nil if it does not follow the format)
+	Violations []lint.Violation // The problems found by the linter
+}
+
+type HistoryWindow struct {
+	screen   tcell.Screen      // The main screen of tcell, shared with the composer
+	title    string            // The title of the window
+	entries  []HistoryEntry    // All the commits of the history
+	list     objects.Rectangle // The rectangle listing the commits
+	details  objects.Rectangle // The rectangle with the message of the selected commit
+	curr_idx int               // The index of the selected commit
+	view     int               // The index of the first visible commit
+}
+
+// Parses and lints all the given commits
+func GetHistoryEntries(commits []util.Commit, linter *lint.Linter) []HistoryEntry {
+	entries := make([]HistoryEntry, 0, len(commits))
+	for _, commit := range commits {
+		msg, err := message.Parse(commit.Message)
+		if err != nil {
+			msg = nil
+		}
+
+		entries = append(entries, HistoryEntry{commit, msg, linter.Lint(commit.Message)})
+	}
+
+	return entries
+}
+
+// Returns the parsed message of the given commit. Messages not following
+// the convention are kept as they are, with the header as subject.
+func LoadCommitMessage(commit string) (*message.Message, error) {
+	content, err := util.GetCommitMessage(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseCommitMessage(content), nil
+}
+
+// Parses the given commit message. Messages not following the convention
+// are kept as they are, with the header as subject.
+func ParseCommitMessage(content string) *message.Message {
+	if msg, err := message.Parse(content); err == nil {
+		return msg
+	}
+
+	header, body, _ := strings.Cut(content, "\n")
+	return &message.Message{Subject: header, Body: strings.TrimSpace(body)}
+}
+
+// Returns true if the commit does not follow the convention
+func (he HistoryEntry) IsInvalid() bool {
+	return he.Msg == nil || lint.HasErrors(he.Violations)
+}
+
+// Returns the columns of the commit: hash, type, scope, gitmoji and subject
+func (he HistoryEntry) getColumns() []string {
+	if he.Msg == nil {
+		header := strings.SplitN(he.Commit.Message, "\n", 2)[0]
+		return []string{he.Commit.GetShortSha(), "", "", "", header}
+	}
+
+	change_type := he.Msg.Type
+	if he.Msg.Breaking {
+		change_type += "!"
+	}
+
+	return []string{he.Commit.GetShortSha(), change_type, he.Msg.Scope, he.Msg.Emoji, he.Msg.Subject}
+}
+
+func HistoryWindow_new(screen tcell.Screen, title string, entries []HistoryEntry) *HistoryWindow {
+	size_w, size_h := screen.Size()
+
+	// The list takes the upper half of the screen, the details the rest
+	list_h := (size_h - 4) / 2
+	hw := new(HistoryWindow)
+	hw.screen = screen
+	hw.title = title
+	hw.entries = entries
+	hw.list = objects.Rectangle{Width: size_w - 10, Height: list_h, Start_x: 5, Start_y: 3}
+	hw.details = objects.Rectangle{Width: size_w - 10, Height: size_h - 5 - list_h,
+		Start_x: 5, Start_y: 4 + list_h}
+	hw.curr_idx = 0
+	hw.view = 0
+
+	return hw
+}
+
+// Returns the number of commits visible at the same time
+func (hw *HistoryWindow) getMaxNofLines() int {
+	return hw.list.Height - 4
+}
+
+// Returns the columns padded to their width and joined into a row
+func (hw *HistoryWindow) formatRow(columns []string) string {
+	row := ""
+	for idx, column := range columns {
+		width := hw.list.Width - 4 - runewidth.StringWidth(row)
+		if idx < len(HISTORY_COLUMNS) {
+			width = HISTORY_COLUMNS[idx]
+		}
+
+		column = runewidth.Truncate(column, width-1, "…")
+		row += runewidth.FillRight(column, width)
+	}
+
+	return row
+}
+
+// Clears the inside of the given rectangle
+func (hw *HistoryWindow) clearRectangle(rec objects.Rectangle) {
+	blank := strings.Repeat(" ", rec.Width-2)
+	for row_idx := 1; row_idx < rec.Height-1; row_idx++ {
+		display.DrawString(hw.screen, blank, rec.Start_x+1, rec.Start_y+row_idx, styles.SimpleStyle)
+	}
+}
+
+func (hw *HistoryWindow) displayList() {
+	hw.clearRectangle(hw.list)
+	start_x, start_y := hw.list.Start_x+2, hw.list.Start_y+1
+	columns := []string{"COMMIT", "TYPE", "SCOPE", "GITMOJI", "SUBJECT"}
+	display.DrawString(hw.screen, hw.formatRow(columns), start_x, start_y, styles.TextBoxTitle)
+
+	end_idx := min(len(hw.entries), hw.view+hw.getMaxNofLines())
+	for idx := hw.view; idx < end_idx; idx++ {
+		// Commits not following the convention are highlighted
+		style := styles.SimpleStyle
+		if hw.entries[idx].IsInvalid() {
+			style = styles.WarningStyle
+		}
+
+		if idx == hw.curr_idx {
+			style = styles.SelectStyle
+		}
+
+		row := hw.formatRow(hw.entries[idx].getColumns())
+		display.DrawString(hw.screen, row, start_x, start_y+2+idx-hw.view, style)
+	}
+
+	if len(hw.entries) < 1 {
+		display.DrawString(hw.screen, "No commits yet", start_x, start_y+2, styles.SimpleStyle)
+	}
+}
+
+func (hw *HistoryWindow) displayDetails() {
+	hw.clearRectangle(hw.details)
+	if len(hw.entries) < 1 {
+		return
+	}
+
+	// The full message, followed by the problems found by the linter
+	entry := hw.entries[hw.curr_idx]
+	lines := []string{fmt.Sprintf("commit %s (%s)", entry.Commit.Sha, entry.Commit.Date), ""}
+	lines = append(lines, strings.Split(entry.Commit.Message, "\n")...)
+	if len(entry.Violations) > 0 {
+		lines = append(lines, "")
+	}
+
+	for _, violation := range entry.Violations {
+		lines = append(lines, violation.String())
+	}
+
+	start_x, start_y := hw.details.Start_x+2, hw.details.Start_y+1
+	max_width := hw.details.Width - 4
+	for idx := 0; idx < min(len(lines), hw.details.Height-2); idx++ {
+		style := styles.SimpleStyle
+		if idx >= len(lines)-len(entry.Violations) {
+			style = styles.WarningStyle
+		}
+
+		line := runewidth.Truncate(lines[idx], max_width, "…")
+		display.DrawString(hw.screen, line, start_x, start_y+idx, style)
+	}
+}
+
+func (hw *HistoryWindow) Display() {
+	hw.screen.Clear()
+	hw.screen.HideCursor()
+	hw.list.DrawRectangle(hw.screen)
+	hw.details.DrawRectangle(hw.screen)
+	display.DrawString(hw.screen, hw.title, hw.list.Start_x+3, hw.list.Start_y, styles.TextBoxTitle)
+	display.DrawString(hw.screen, HISTORY_HELP, hw.list.Start_x, 1, styles.SubTitleStyle)
+	hw.displayList()
+	hw.displayDetails()
+	hw.screen.Show()
+}
+
+// Moves the selection by the given number of commits, scrolling if needed
+func (hw *HistoryWindow) moveSelection(offset int) {
+	hw.curr_idx = max(0, min(len(hw.entries)-1, hw.curr_idx+offset))
+	if hw.curr_idx < hw.view {
+		hw.view = hw.curr_idx
+	} else if hw.curr_idx >= hw.view+hw.getMaxNofLines() {
+		hw.view = hw.curr_idx - hw.getMaxNofLines() + 1
+	}
+
+	hw.displayList()
+	hw.displayDetails()
+	hw.screen.Show()
+}
+
+// Displays the history until it is closed with ESC or F2
+func (hw *HistoryWindow) Run() {
+	hw.Display()
+	for {
+		switch ev := hw.screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			switch ev.Key() {
+			case tcell.KeyEscape, tcell.KeyF2:
+				return
+			case tcell.KeyUp:
+				hw.moveSelection(-1)
+			case tcell.KeyDown:
+				hw.moveSelection(1)
+			case tcell.KeyPgUp:
+				hw.moveSelection(-hw.getMaxNofLines())
+			case tcell.KeyPgDn:
+				hw.moveSelection(hw.getMaxNofLines())
+			}
+
+		case *tcell.EventResize:
+			hw.screen.Sync()
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/*
+This package contains the rules that a commit message must follow, i.e., the
+same rules implied by the commit composer, and the linter applying them.
+*/
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+)
+
+// The severity of a rule, the same levels used by commitlint
+type Severity int
+
+const (
+	DISABLED Severity = 0
+	WARNING  Severity = 1
+	ERROR    Severity = 2
+)
+
+// Messages generated by git itself are not linted
+var IGNORED_PREFIXES = []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "}
+
+type Violation struct {
+	Rule     string   // The identifier of the violated rule
+	Severity Severity // The severity of the violated rule
+	Message  string   // The description of the violation
+}
+
+type Rule struct {
+	Id       string                          // The identifier of the rule
+	Severity Severity                        // The severity of the rule
+	Check    func(*message.Message) []string // Returns the problems found, if any
+}
+
+type Linter struct {
+	Rules []Rule // All the rules applied to messages
+}
+
+func (s Severity) String() string {
+	switch s {
+	case WARNING:
+		return "warning"
+	case ERROR:
+		return "error"
+	default:
+		return "disabled"
+	}
+}
+
+// Returns a sorted copy of the keys of the map
+func sortedKeys(content map[string]string) []string {
+	keys := make([]string, 0, len(content))
+	for key := range content {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Returns true if the emoji, either unicode or shortcode, is a known gitmoji.
+// Variation selectors are ignored, since they are often omitted.
+func isKnownGitmoji(gitmoji map[string]string, emoji string) bool {
+	emoji = strings.ReplaceAll(emoji, "\ufe0f", "")
+	for key, description := range gitmoji {
+		if strings.ReplaceAll(key, "\ufe0f", "") == emoji ||
+			strings.HasPrefix(description, emoji+" ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if the emoji, either unicode or shortcode, is the given gitmoji
+func isSameGitmoji(gitmoji map[string]string, expected, emoji string) bool {
+	if strings.ReplaceAll(expected, "\ufe0f", "") == strings.ReplaceAll(emoji, "\ufe0f", "") {
+		return true
+	}
+
+	return strings.HasPrefix(gitmoji[expected], emoji+" ")
+}
+
+// Creates a linter with the default rules, checking that the type is one of
+// the given types, that the emoji is one of the given gitmoji and that it is
+// the gitmoji suggested for the type
+func Linter_new(types, gitmoji, type_gitmoji map[string]string) *Linter {
+	linter := new(Linter)
+	linter.Rules = []Rule{
+		{"type-enum", ERROR, func(msg *message.Message) []string {
+			if _, ok := types[strings.ToUpper(msg.Type)]; ok {
+				return nil
+			}
+
+			allowed := strings.ToLower(strings.Join(sortedKeys(types), ", "))
+			return []string{fmt.Sprintf("type %q must be one of: %s", msg.Type, allowed)}
+		}},
+		{"type-case", ERROR, func(msg *message.Message) []string {
+			if msg.Type == strings.ToLower(msg.Type) {
+				return nil
+			}
+
+			return []string{fmt.Sprintf("type %q must be lower-case", msg.Type)}
+		}},
+		{"gitmoji-empty", WARNING, func(msg *message.Message) []string {
+			if len(msg.Emoji) > 0 {
+				return nil
+			}
+
+			return []string{"gitmoji should not be empty"}
+		}},
+		{"gitmoji-enum", ERROR, func(msg *message.Message) []string {
+			if len(msg.Emoji) < 1 || isKnownGitmoji(gitmoji, msg.Emoji) {
+				return nil
+			}
+
+			return []string{fmt.Sprintf("gitmoji %q is not a known gitmoji", msg.Emoji)}
+		}},
+		{"gitmoji-type", WARNING, func(msg *message.Message) []string {
+			expected, ok := type_gitmoji[strings.ToUpper(msg.Type)]
+			if !ok || len(msg.Emoji) < 1 || isSameGitmoji(gitmoji, expected, msg.Emoji) {
+				return nil
+			}
+
+			return []string{fmt.Sprintf("gitmoji %q does not match type %q, expected %s",
+				msg.Emoji, msg.Type, expected)}
+		}},
+		{"gitmoji-forbidden", DISABLED, func(msg *message.Message) []string {
+			if len(msg.Emoji) < 1 {
+				return nil
+			}
+
+			return []string{fmt.Sprintf("gitmoji %q is not allowed", msg.Emoji)}
+		}},
+		{"subject-empty", ERROR, func(msg *message.Message) []string {
+			if len(strings.TrimSpace(msg.Subject)) > 0 {
+				return nil
+			}
+
+			return []string{"subject may not be empty"}
+		}},
+		{"body-empty", ERROR, func(msg *message.Message) []string {
+			if len(strings.TrimSpace(msg.Body)) > 0 {
+				return nil
+			}
+
+			return []string{"body may not be empty"}
+		}},
+	}
+
+	return linter
+}
+
+// Returns a rule checking that the header is not longer than the given length
+func HeaderLengthRule_new(id string, severity Severity, max_length int) Rule {
+	return Rule{id, severity, func(msg *message.Message) []string {
+		header_len := utf8.RuneCountInString(msg.Header())
+		if header_len <= max_length {
+			return nil
+		}
+
+		return []string{fmt.Sprintf("header is %d characters long, limit is %d", header_len, max_length)}
+	}}
+}
+
+// Changes the severity of the rule with the given identifier
+func (l *Linter) SetSeverity(id string, severity Severity) {
+	for idx := range l.Rules {
+		if l.Rules[idx].Id == id {
+			l.Rules[idx].Severity = severity
+		}
+	}
+}
+
+// Replaces the rule with the same identifier, or adds it if there is none
+func (l *Linter) SetRule(rule Rule) {
+	for idx := range l.Rules {
+		if l.Rules[idx].Id == rule.Id {
+			l.Rules[idx] = rule
+			return
+		}
+	}
+
+	l.Rules = append(l.Rules, rule)
+}
+
+// Returns true if the message has been generated by git and it is not linted
+func IsIgnored(content string) bool {
+	for _, prefix := range IGNORED_PREFIXES {
+		if strings.HasPrefix(content, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns all the violations of the rules found in the given message
+func (l *Linter) Lint(content string) []Violation {
+	violations := make([]Violation, 0)
+	content = strings.TrimSpace(content)
+	if IsIgnored(content) {
+		return violations
+	}
+
+	// A message that cannot be parsed cannot be checked any further
+	msg, err := message.Parse(content)
+	if err != nil {
+		return append(violations, Violation{"header-format", ERROR, err.Error()})
+	}
+
+	for _, rule := range l.Rules {
+		if rule.Severity == DISABLED {
+			continue
+		}
+
+		for _, problem := range rule.Check(msg) {
+			violations = append(violations, Violation{rule.Id, rule.Severity, problem})
+		}
+	}
+
+	return violations
+}
+
+// Returns true if at least one violation is an error
+func HasErrors(violations []Violation) bool {
+	for _, violation := range violations {
+		if violation.Severity == ERROR {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the violation formatted for being displayed
+func (v Violation) String() string {
+	return fmt.Sprintf("%-7s [%s] %s", v.Severity, v.Rule, v.Message)
+}
@@ -0,0 +1,68 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+)
+
+var TYPES = map[string]string{"FEAT": "A new feature", "FIX": "A bug fix"}
+var GITMOJI = map[string]string{"✨": ":sparkles: Introduce new features", "🐛": ":bug: Fix a bug"}
+var TYPE_GITMOJI = map[string]string{"FEAT": "✨", "FIX": "🐛"}
+
+// Returns the identifiers of the violated rules
+func getRuleIds(violations []Violation) []string {
+	ids := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		ids = append(ids, violation.Rule)
+	}
+
+	return ids
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"feat: ✨ add templates\n\nThe body.", []string{}},
+		{"feat: :sparkles: add templates\n\nThe body.", []string{}},
+		{"✨ feat: add templates\n\nThe body.", []string{}},
+		{"feat: add templates\n\nThe body.", []string{"gitmoji-empty"}},
+		{"feat: ✨ add templates", []string{"body-empty"}},
+		{"docs: ✨ add templates\n\nThe body.", []string{"type-enum"}},
+		{"Feat: ✨ add templates\n\nThe body.", []string{"type-case"}},
+		{"feat: 🎸 add templates\n\nThe body.", []string{"gitmoji-enum", "gitmoji-type"}},
+		{"fix: ✨ close the file\n\nThe body.", []string{"gitmoji-type"}},
+		{"add templates", []string{"header-format"}},
+		{"Merge branch 'main' into dev", []string{}},
+		{"fixup! feat: ✨ add templates", []string{}},
+	}
+
+	linter := Linter_new(TYPES, GITMOJI, TYPE_GITMOJI)
+	for _, test := range tests {
+		if ids := getRuleIds(linter.Lint(test.content)); !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("Lint(%q) violates %v, want %v", test.content, ids, test.want)
+		}
+	}
+}
+
+func TestLinterRules(t *testing.T) {
+	linter := Linter_new(TYPES, GITMOJI, TYPE_GITMOJI)
+	linter.SetSeverity("gitmoji-empty", DISABLED)
+	linter.SetSeverity("gitmoji-forbidden", ERROR)
+	linter.SetRule(HeaderLengthRule_new("header-max-length", ERROR, 20))
+
+	violations := linter.Lint("feat: ✨ add the history window\n\nThe body.")
+	want := []string{"gitmoji-forbidden", "header-max-length"}
+	if ids := getRuleIds(violations); !reflect.DeepEqual(ids, want) {
+		t.Errorf("Lint() violates %v, want %v", ids, want)
+	}
+
+	if !HasErrors(violations) {
+		t.Errorf("HasErrors(%v) = false", violations)
+	}
+
+	if violations := linter.Lint("feat: add templates\n\nThe body."); len(violations) > 0 {
+		t.Errorf("Lint() with gitmoji-empty disabled = %v", violations)
+	}
+}
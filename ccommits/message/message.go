@@ -0,0 +1,155 @@
+/*
+This package contains the structured model of a conventional commit message,
+shared by all the features that need to write or read commit messages.
+*/
+package message
+
+import (
+	"strings"
+)
+
+// The footer tokens marking a breaking change (both are allowed by the spec)
+const BREAKING_CHANGE string = "BREAKING CHANGE"
+const BREAKING_CHANGE_ALT string = "BREAKING-CHANGE"
+
+type Trailer struct {
+	Key       string `json:"key"`       // The token of the footer
+	Separator string `json:"separator"` // Either ": " or " #"
+	Value     string `json:"value"`     // The value, possibly spanning multiple lines
+}
+
+type Message struct {
+	Type     string    `json:"type"`     // The type of the change
+	Scope    string    `json:"scope"`    // The scope of the change (Optional)
+	Breaking bool      `json:"breaking"` // If the header is marked with the ! before the colon
+	Emoji    string    `json:"emoji"`    // The gitmoji before the subject (Optional)
+	Prefix   bool      `json:"prefix"`   // If the gitmoji is placed before the type
+	Subject  string    `json:"subject"`  // The short description of the change
+	Body     string    `json:"body"`     // The longer description of the change (Optional)
+	Trailers []Trailer `json:"trailers"` // All the footers of the message
+}
+
+// Returns true if the given footer token marks a breaking change
+func IsBreakingToken(key string) bool {
+	return key == BREAKING_CHANGE || key == BREAKING_CHANGE_ALT
+}
+
+// Returns the value of the trailer as it should be displayed
+func (t Trailer) GetValue() string {
+	return strings.TrimSpace(t.Value)
+}
+
+// Returns the trailer formatted as a footer line
+func (t Trailer) Format() string {
+	separator := t.Separator
+	if len(separator) < 1 {
+		separator = ": "
+	}
+
+	return t.Key + separator + t.Value
+}
+
+// Returns true if the change is breaking, either because of the ! in the
+// header or because of a BREAKING CHANGE footer
+func (m *Message) IsBreaking() bool {
+	return m.Breaking || len(m.GetBreakingChanges()) > 0
+}
+
+// Returns the description of all the breaking change footers
+func (m *Message) GetBreakingChanges() []string {
+	descriptions := make([]string, 0)
+	for _, trailer := range m.Trailers {
+		if IsBreakingToken(trailer.Key) {
+			descriptions = append(descriptions, trailer.GetValue())
+		}
+	}
+
+	return descriptions
+}
+
+// Returns the values of all the trailers with the given key
+func (m *Message) GetTrailers(key string) []string {
+	values := make([]string, 0)
+	for _, trailer := range m.Trailers {
+		if strings.EqualFold(trailer.Key, key) {
+			values = append(values, trailer.GetValue())
+		}
+	}
+
+	return values
+}
+
+// Appends a new footer, written as "key: value", to the message
+func (m *Message) AddTrailer(key, value string) {
+	m.InsertTrailer(Trailer{key, ": ", value})
+}
+
+// Appends the given footer to the message. Breaking changes are always placed
+// first and in their own paragraph, since the BREAKING CHANGE token is not
+// a valid git trailer and would prevent git from parsing the others.
+func (m *Message) InsertTrailer(trailer Trailer) {
+	// Find where the new trailer needs to be inserted
+	insert_idx := len(m.Trailers)
+	if IsBreakingToken(trailer.Key) {
+		insert_idx = 0
+		for insert_idx < len(m.Trailers) && IsBreakingToken(m.Trailers[insert_idx].Key) {
+			insert_idx++
+		}
+	}
+
+	trailers := append(make([]Trailer, 0, len(m.Trailers)+1), m.Trailers[:insert_idx]...)
+	trailers = append(trailers, trailer)
+	m.Trailers = append(trailers, m.Trailers[insert_idx:]...)
+}
+
+// Returns the first line of the message. The gitmoji is placed either
+// after the colon or, as prefix, before the type.
+func (m *Message) Header() string {
+	header := m.Type
+	if len(m.Scope) > 0 {
+		header += "(" + m.Scope + ")"
+	}
+
+	if m.Breaking {
+		header += "!"
+	}
+
+	header += ": "
+	if len(m.Emoji) > 0 && m.Prefix {
+		header = m.Emoji + " " + header
+	} else if len(m.Emoji) > 0 {
+		header += m.Emoji + " "
+	}
+
+	return header + m.Subject
+}
+
+// Returns all the footers, one per line. The last breaking change is
+// followed by an empty line whenever other trailers come after it.
+func (m *Message) Footer() string {
+	lines := make([]string, 0, len(m.Trailers)+1)
+	for idx, trailer := range m.Trailers {
+		if idx > 0 && IsBreakingToken(m.Trailers[idx-1].Key) && !IsBreakingToken(trailer.Key) {
+			lines = append(lines, "")
+		}
+
+		lines = append(lines, trailer.Format())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Returns the whole commit message. The header, the body and the footer
+// are separated by an empty line.
+func (m *Message) Format() string {
+	paragraphs := []string{m.Header()}
+	if len(m.Body) > 0 {
+		paragraphs = append(paragraphs, m.Body)
+	}
+
+	if len(m.Trailers) > 0 {
+		paragraphs = append(paragraphs, m.Footer())
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
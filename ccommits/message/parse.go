@@ -0,0 +1,182 @@
+package message
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// The header, without the emoji, is type(scope)!: description
+var HEADER_RE = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+// A footer starts with a token followed by either ": " or " #"
+var FOOTER_RE = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z0-9][A-Za-z0-9_-]*)(: | #)(.*)$`)
+
+// A gitmoji can be written also with its shortcode, e.g., :sparkles:
+var SHORTCODE_RE = regexp.MustCompile(`^(:[a-z0-9_+-]+:) (.*)$`)
+
+// Returns true if the rune can be part of an emoji sequence
+func isEmojiRune(char rune) bool {
+	return char == 0x200D || // Zero width joiner
+		(char >= 0xFE00 && char <= 0xFE0F) || // Variation selectors
+		(char >= 0x1F3FB && char <= 0x1F3FF) || // Skin tone modifiers
+		(char >= 0xE0020 && char <= 0xE007F) || // Tags
+		(char > unicode.MaxASCII && unicode.Is(unicode.So, char))
+}
+
+// Split the emoji at the beginning of the string from the rest. Returns
+// an empty emoji if the string does not start with an emoji and a space.
+func SplitEmoji(content string) (string, string) {
+	if matches := SHORTCODE_RE.FindStringSubmatch(content); matches != nil {
+		return matches[1], matches[2]
+	}
+
+	for idx, char := range content {
+		if isEmojiRune(char) {
+			continue
+		}
+
+		if idx > 0 && char == ' ' {
+			return content[:idx], content[idx+1:]
+		}
+
+		break
+	}
+
+	return "", content
+}
+
+// Parse the first line of a commit message
+func parseHeader(header string, msg *Message) error {
+	// The gitmoji might be placed before the type
+	prefix, rest := SplitEmoji(header)
+	has_prefix := len(prefix) > 0 && HEADER_RE.MatchString(rest)
+	if has_prefix {
+		header = rest
+	}
+
+	matches := HEADER_RE.FindStringSubmatch(header)
+	if matches == nil {
+		return errors.New("the header must be in the form type(scope)!: description")
+	}
+
+	// The scope is given when its group matched something, even an empty string
+	scope_given := HEADER_RE.FindStringSubmatchIndex(header)[4] >= 0
+	if scope_given && len(strings.TrimSpace(matches[2])) < 1 {
+		return errors.New("the scope must not be empty when the parenthesis are given")
+	}
+
+	if len(strings.TrimSpace(matches[4])) < 1 {
+		return errors.New("the description must follow the colon and the space")
+	}
+
+	msg.Type = matches[1]
+	msg.Scope = matches[2]
+	msg.Breaking = len(matches[3]) > 0
+	msg.Emoji, msg.Subject = SplitEmoji(matches[4])
+	if has_prefix {
+		msg.Emoji, msg.Subject = prefix, matches[4]
+		msg.Prefix = true
+	}
+
+	return nil
+}
+
+// Parse the footers. Each footer value goes on until the next footer
+// token, hence it can contain multiple lines and even empty ones. The
+// empty lines separating a value from the next footer are not part of it.
+func parseFooter(lines []string) []Trailer {
+	trailers := make([]Trailer, 0)
+	for _, line := range lines {
+		if matches := FOOTER_RE.FindStringSubmatch(line); matches != nil {
+			trailers = append(trailers, Trailer{matches[1], matches[2], matches[3]})
+			continue
+		}
+
+		last := &trailers[len(trailers)-1]
+		last.Value += "\n" + line
+	}
+
+	for idx := range trailers {
+		trailers[idx].Value = strings.TrimRight(trailers[idx].Value, "\n")
+	}
+
+	return trailers
+}
+
+// Returns the index of the first line of the footer, i.e., the first line
+// starting with a footer token right after an empty line. If there is no
+// footer the number of lines is returned.
+func findFooterStart(lines []string) int {
+	for idx, line := range lines {
+		if idx > 0 && len(strings.TrimSpace(lines[idx-1])) > 0 {
+			continue
+		}
+
+		if FOOTER_RE.MatchString(line) {
+			return idx
+		}
+	}
+
+	return len(lines)
+}
+
+// Parse a conventional commit message into its structured form. Parsing
+// and formatting back the message gives the very same message, apart from
+// leading and trailing empty lines that are removed, and the empty lines
+// between the footers, which are placed as Format does.
+func Parse(content string) (*Message, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.Trim(content, "\n")
+	lines := strings.Split(content, "\n")
+
+	msg := new(Message)
+	msg.Trailers = make([]Trailer, 0)
+	if err := parseHeader(lines[0], msg); err != nil {
+		return nil, err
+	}
+
+	if len(lines) < 2 {
+		return msg, nil
+	}
+
+	// Body and footer must begin one empty line after the header
+	if len(strings.TrimSpace(lines[1])) > 0 {
+		return nil, errors.New("the body must begin one empty line after the header")
+	}
+
+	lines = lines[2:]
+	footer_idx := findFooterStart(lines)
+	if footer_idx > 0 {
+		// The empty line before the footer separates it from the body
+		body_end := footer_idx
+		if footer_idx < len(lines) {
+			body_end--
+		}
+
+		msg.Body = strings.Join(lines[:body_end], "\n")
+	}
+
+	msg.Trailers = parseFooter(lines[footer_idx:])
+	return msg, nil
+}
+
+// Removes from the message all the comment lines and everything below the
+// scissors line, as git does when reading the commit message file
+func Cleanup(content string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,122 @@
+package message
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	messages := []string{
+		"feat: add the history window",
+		"fix(parser): handle empty scopes",
+		"feat(api)!: drop the v1 endpoints",
+		"feat: ✨ add templates",
+		"✨ feat(ui): add templates",
+		"feat: :sparkles: add templates",
+		"fix: 👨‍👩‍👧 support zero width joiners",
+		"docs: update the readme\n\nThe body of the message.",
+		"docs: update the readme\n\nFirst paragraph\nspanning two lines.\n\n- a list item\n- another one",
+		"fix: close the file\n\nRefs: #42",
+		"fix: close the file\n\nFixes #42",
+		"feat!: new config\n\nThe body.\n\nBREAKING CHANGE: the config format changed\n\nRefs: #1\nReviewed-by: Z",
+		"feat!: new config\n\nBREAKING CHANGE: first\nBREAKING-CHANGE: second",
+		"feat: multi-line footer\n\nBREAKING CHANGE: the description\ngoes on here\n\nand here",
+		"refactor: body looking like a footer\n\nThis is: not a footer\n\nRefs: #3",
+	}
+
+	for _, content := range messages {
+		msg, err := Parse(content)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %s", content, err)
+			continue
+		}
+
+		if formatted := msg.Format(); formatted != content {
+			t.Errorf("Parse(%q).Format() = %q", content, formatted)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		content string
+		want    Message
+	}{
+		{"feat(ui)!: ✨ add templates",
+			Message{Type: "feat", Scope: "ui", Breaking: true, Emoji: "✨", Subject: "add templates"}},
+		{"✨ feat: add templates",
+			Message{Type: "feat", Emoji: "✨", Prefix: true, Subject: "add templates"}},
+		{"fix: a\r\n\r\nbody\r\n\r\nRefs: #1\r\n",
+			Message{Type: "fix", Subject: "a", Body: "body", Trailers: []Trailer{{"Refs", ": ", "#1"}}}},
+		{"feat!: a\n\nBREAKING CHANGE: b\n\nRefs: #1",
+			Message{Type: "feat", Breaking: true, Subject: "a",
+				Trailers: []Trailer{{"BREAKING CHANGE", ": ", "b"}, {"Refs", ": ", "#1"}}}},
+		{"fix: a\n\nFixes #12\nAcked-by: X",
+			Message{Type: "fix", Subject: "a",
+				Trailers: []Trailer{{"Fixes", " #", "12"}, {"Acked-by", ": ", "X"}}}},
+	}
+
+	for _, test := range tests {
+		msg, err := Parse(test.content)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %s", test.content, err)
+			continue
+		}
+
+		if test.want.Trailers == nil {
+			test.want.Trailers = []Trailer{}
+		}
+
+		if !reflect.DeepEqual(*msg, test.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", test.content, *msg, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"add templates",
+		"feat add templates",
+		"feat(): add templates",
+		"feat:add templates",
+		"feat: ",
+		"feat: a\nbody right after the header",
+	}
+
+	for _, content := range invalid {
+		if _, err := Parse(content); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", content)
+		}
+	}
+}
+
+func TestAddTrailer(t *testing.T) {
+	msg := &Message{Type: "feat", Subject: "a"}
+	msg.AddTrailer("Refs", "#1")
+	msg.AddTrailer(BREAKING_CHANGE, "first")
+	msg.AddTrailer("Reviewed-by", "Z")
+	msg.AddTrailer(BREAKING_CHANGE, "second")
+
+	want := "feat: a\n\nBREAKING CHANGE: first\nBREAKING CHANGE: second\n\nRefs: #1\nReviewed-by: Z"
+	if formatted := msg.Format(); formatted != want {
+		t.Errorf("Format() = %q, want %q", formatted, want)
+	}
+
+	// The values hold no formatting, hence they survive a round-trip
+	parsed, err := Parse(msg.Format())
+	if err != nil {
+		t.Fatalf("Parse failed: %s", err)
+	}
+
+	if !reflect.DeepEqual(parsed.Trailers, msg.Trailers) {
+		t.Errorf("Parse(Format()).Trailers = %+v, want %+v", parsed.Trailers, msg.Trailers)
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	content := "feat: a\n# a comment\n\nbody  \n\n# ------------------------ >8 ------------------------\ndiff"
+	if cleaned := Cleanup(content); cleaned != "feat: a\n\nbody" {
+		t.Errorf("Cleanup() = %q", cleaned)
+	}
+}
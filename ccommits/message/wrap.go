@@ -0,0 +1,97 @@
+package message
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// The marker of a bullet or numbered list item, e.g., "- ", "* " or "1. "
+var LIST_ITEM_RE = regexp.MustCompile(`^(\s*)([-*+]|[0-9]+[.)])\s+`)
+
+// The fence opening or closing a code block
+const CODE_FENCE string = "```"
+
+// Returns true if the line belongs to an indented code block
+func isIndentedCode(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// Fills the words into lines of at most the given width. The first line
+// starts with the given prefix, the others with the given indentation.
+// Words are never broken, hence longer words (e.g., URLs) overflow.
+func fillWords(words []string, width int, prefix, indent string) []string {
+	lines := make([]string, 0)
+	curr_line := prefix
+	curr_empty := true
+
+	for _, word := range words {
+		if !curr_empty && utf8.RuneCountInString(curr_line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, curr_line)
+			curr_line, curr_empty = indent, true
+		}
+
+		if !curr_empty {
+			curr_line += " "
+		}
+
+		curr_line += word
+		curr_empty = false
+	}
+
+	return append(lines, curr_line)
+}
+
+// Wraps a single line longer than the given width. The lines that follow
+// keep the indentation of the line, or are aligned to the text of a list item.
+func wrapLine(line string, width int) []string {
+	if utf8.RuneCountInString(line) <= width {
+		return []string{line}
+	}
+
+	prefix := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	indent := prefix
+	if matches := LIST_ITEM_RE.FindString(line); len(matches) > 0 {
+		prefix = matches
+		indent = strings.Repeat(" ", utf8.RuneCountInString(matches))
+	}
+
+	return fillWords(strings.Fields(line[len(prefix):]), width, prefix, indent)
+}
+
+// Wraps the lines of the body longer than the given width. The line breaks
+// already in the body are kept, e.g., of short lines or of text wrapped by
+// hand, as well as code blocks, either indented or fenced. A width smaller
+// than 1 disables wrapping.
+func Wrap(body string, width int) string {
+	if width < 1 {
+		return body
+	}
+
+	lines := make([]string, 0)
+	in_fence := false
+	in_text := false // If the previous line is text, which indentation continues
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, CODE_FENCE):
+			in_fence = !in_fence
+			in_text = false
+			lines = append(lines, line)
+
+		case in_fence || isIndentedCode(line) && !in_text:
+			lines = append(lines, line)
+
+		case len(trimmed) < 1:
+			in_text = false
+			lines = append(lines, "")
+
+		default:
+			in_text = true
+			lines = append(lines, wrapLine(line, width)...)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,42 @@
+package message
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		body  string
+		width int
+		want  string
+	}{
+		{"short body", 72, "short body"},
+		{"one two three four five", 9, "one two\nthree\nfour five"},
+		// Line breaks are kept, while only the long lines are wrapped
+		{"one two\nthree four", 72, "one two\nthree four"},
+		{"one two three four five\nsix", 9, "one two\nthree\nfour five\nsix"},
+		{"  indented text that is long", 10, "  indented\n  text\n  that is\n  long"},
+		{"first paragraph\n\nsecond paragraph", 10, "first\nparagraph\n\nsecond\nparagraph"},
+		{"- one two three\n- four", 9, "- one two\n  three\n- four"},
+		{"1. one two three", 9, "1. one\n   two\n   three"},
+		{"see https://example.com/a/very/long/url", 10, "see\nhttps://example.com/a/very/long/url"},
+		{"text\n\n    indented code that is long", 10, "text\n\n    indented code that is long"},
+		{"```\nfenced code that is long\n```\nafter the fence", 10,
+			"```\nfenced code that is long\n```\nafter the\nfence"},
+		{"unchanged when disabled", 0, "unchanged when disabled"},
+		{"àèìòù àèìòù", 11, "àèìòù àèìòù"},
+	}
+
+	for _, test := range tests {
+		if wrapped := Wrap(test.body, test.width); wrapped != test.want {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", test.body, test.width, wrapped, test.want)
+		}
+	}
+}
+
+func TestWrapIsIdempotent(t *testing.T) {
+	body := "A paragraph long enough to be wrapped over multiple lines of text.\n\n" +
+		"- a list item long enough to be wrapped as well\n- short item"
+	wrapped := Wrap(body, 20)
+	if again := Wrap(wrapped, 20); again != wrapped {
+		t.Errorf("Wrap(Wrap(body)) = %q, want %q", again, wrapped)
+	}
+}
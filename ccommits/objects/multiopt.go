@@ -1,6 +1,8 @@
 package objects
 
 import (
+	"sort"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/display"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/styles"
@@ -39,6 +41,14 @@ func MultiOptionBox_new(title string, x, y, size_w, size_h int, content map[stri
 	return &MultiOptionBox{*rect, content, keys, 0, title, false, 0}
 }
 
+// Sorts the options by key, which are otherwise listed in no specific order.
+// The first option gets selected.
+func (mob *MultiOptionBox) Sort() {
+	sort.Strings(mob.keys)
+	mob.curr_idx = 0
+	mob.view = 0
+}
+
 func (mob *MultiOptionBox) getMaxNofLines() int {
 	return mob.rec.Height - 5
 }
@@ -155,6 +165,28 @@ func (mob *MultiOptionBox) handleArrowPressed(screen tcell.Screen, direction int
 	display.DrawString(screen, content, pos_x, pos_y, styles.SelectStyle)
 }
 
+// Selects the option with the given key, scrolling the content when the
+// option is not in the current view. Returns false if there is no such key.
+func (mob *MultiOptionBox) Select(screen tcell.Screen, key string) bool {
+	for idx, curr_key := range mob.keys {
+		if curr_key != key {
+			continue
+		}
+
+		mob.curr_idx = idx
+		mob.view = mob.getView(idx)
+
+		// Redraw the view containing the selected option
+		start_line := mob.view * mob.getMaxNofLines()
+		stop_line := min((mob.view+1)*mob.getMaxNofLines(), len(mob.content))
+		mob.clearContent(screen)
+		mob.drawContent(screen, start_line, stop_line)
+		return true
+	}
+
+	return false
+}
+
 func (mob *MultiOptionBox) Display(screen tcell.Screen) {
 	mob.rec.DrawRectangle(screen) // Draw the rectangle for the text box
 
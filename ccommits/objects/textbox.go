@@ -1,9 +1,15 @@
 package objects
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/display"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/styles"
+	"github.com/mattn/go-runewidth"
 )
 
 type Vec2 struct {
@@ -19,17 +25,30 @@ var ARROW_MAPPING map[tcell.Key]Vec2 = map[tcell.Key]Vec2{
 	tcell.KeyRight: {+1, 0},
 }
 
+// The markers on the border of a textbox whose content is scrolled
+const SCROLL_UP string = "▲"
+const SCROLL_DOWN string = "▼"
+
 type TextBox struct {
 	rec         Rectangle // The rectangle of the text box
 	title       string    // The title of the text box (Optional)
 	start_pos_x int       // Start position x of the first character
 	start_pos_y int       // Start position y of the first character
 	content     string    // The content of the text box
-	curr_pos_x  int       // The current X position of the cursor in the text
-	curr_pos_y  int       // The current Y position of the cursor in the text
+	curr_idx    int       // The index of the character the cursor is on
+	view        int       // The first row of the content that is displayed
+	multiline   bool      // If ENTER breaks the line instead of being ignored
 	focus       bool      // If the current focus is on this object
-	nof_lines   int       // Total number of lines
-	curr_line   int       // Current line at which the cursor is positioned
+
+	completions  []string // Possible completions of the content (Optional)
+	compl_prefix string   // The content typed before cycling through completions
+	compl_idx    int      // Index of the current completion (-1 if none)
+
+	limited    bool // If the length of the content is limited
+	soft_limit int  // Length after which the content is displayed as a warning
+	hard_limit int  // Length after which no more characters are accepted
+
+	highlight *regexp.Regexp // The parts of the content to highlight (Optional)
 }
 
 func TextBox_new(title string, x, y, size_w, size_h int) *TextBox {
@@ -47,208 +66,279 @@ func TextBox_new(title string, x, y, size_w, size_h int) *TextBox {
 	tb.start_pos_x = startpos_x
 	tb.start_pos_y = startpos_y
 	tb.content = ""
-	tb.curr_pos_x = startpos_x
-	tb.curr_pos_y = startpos_y
+	tb.curr_idx = 0
+	tb.view = 0
+	tb.multiline = false
 	tb.focus = false
-	tb.nof_lines = 0
-	tb.curr_line = 0
+	tb.completions = nil
+	tb.compl_prefix = ""
+	tb.compl_idx = -1
+	tb.limited = false
+	tb.soft_limit = 0
+	tb.hard_limit = 0
+	tb.highlight = nil
 
 	return tb
 }
 
-func (tb *TextBox) displayContentPortion(screen tcell.Screen, start_x, start_y int, content string) {
-	rel_pos_x := start_x - tb.start_pos_x
-	var nof_rows int = (rel_pos_x+len(content))/tb.getMaxRowSize() + 1
+// Returns the position, as column and row, of each character of the content
+// followed by the position after the last one. Rows end at new lines and
+// when the next character does not fit the width of the textbox.
+func (tb *TextBox) getLayout() []Vec2 {
+	row_size := tb.getMaxRowSize()
+	positions := make([]Vec2, 0, len(tb.content)+1)
+	pos := Vec2{0, 0}
+
+	for _, char := range tb.content {
+		width := runewidth.RuneWidth(char)
+		if char != '\n' && pos.X > 0 && pos.X+width > row_size {
+			pos = Vec2{0, pos.Y + 1}
+		}
 
-	content_arr := []rune(content)
-	curr_nof_rows := 0
-	in_content_start_pos := 0
+		positions = append(positions, pos)
+		if char == '\n' {
+			pos = Vec2{0, pos.Y + 1}
+		} else {
+			pos.X += width
+		}
+	}
+
+	// The cursor after a full row goes at the beginning of the next one
+	if pos.X >= row_size {
+		pos = Vec2{0, pos.Y + 1}
+	}
 
-	for curr_nof_rows < nof_rows {
-		// Compute the start and stop indices for taking the substring
-		in_content_stop_pos := min(len(content), in_content_start_pos+(tb.getMaxRowSize()-rel_pos_x))
-		subcontent := content_arr[in_content_start_pos:in_content_stop_pos]
+	return append(positions, pos)
+}
 
-		// Display the substring
-		display.DrawString(screen, string(subcontent), start_x, start_y, styles.SimpleStyle)
+// Returns, for each character of the content, if it matches the highlight
+func (tb *TextBox) getHighlighted() []bool {
+	highlighted := make([]bool, utf8.RuneCountInString(tb.content))
+	if tb.highlight == nil {
+		return highlighted
+	}
 
-		// Update positions
-		curr_nof_rows++
-		start_x = tb.start_pos_x
-		start_y = start_y + curr_nof_rows
-		rel_pos_x = start_x - tb.start_pos_x
-		in_content_start_pos = in_content_stop_pos
+	for _, match := range tb.highlight.FindAllStringIndex(tb.content, -1) {
+		// Matches are in bytes, while positions are in characters
+		start_idx := utf8.RuneCountInString(tb.content[:match[0]])
+		for idx := range utf8.RuneCountInString(tb.content[match[0]:match[1]]) {
+			highlighted[start_idx+idx] = true
+		}
 	}
+
+	return highlighted
 }
 
+// Displays the rows of the content in view. The markers on the right of the
+// border show if there are more rows above or below.
 func (tb *TextBox) displayContent(screen tcell.Screen) {
-	// Display all the content into the screen
-	row_size := tb.getMaxRowSize()      // Get the maximum row size
-	content_array := []rune(tb.content) // From string to rule array
-	content_len := len(tb.content)      // Take the length of the string
-
-	for row_idx := 0; row_idx < tb.nof_lines; row_idx++ {
-		// Get the correct start and stop indexes
-		start_idx := row_idx * row_size
-		stop_idx := min(content_len, (row_idx+1)*row_size)
-		substr := string(content_array[start_idx:stop_idx])
-
-		// Draw the substring content into the screen
-		tb.displayContentPortion(screen, tb.start_pos_x, tb.start_pos_y+row_idx, substr)
+	tb.clearContent(screen)
+	layout := tb.getLayout()
+	highlighted := tb.getHighlighted()
+	style := tb.getStyle()
+
+	for idx, char := range []rune(tb.content) {
+		row := layout[idx].Y - tb.view
+		if char == '\n' || row < 0 || row >= tb.getMaxRows() {
+			continue
+		}
+
+		char_style := style
+		if highlighted[idx] {
+			char_style = styles.PlaceholderStyle
+		}
+
+		display.DrawString(screen, string(char), tb.start_pos_x+layout[idx].X, tb.start_pos_y+row, char_style)
 	}
-}
 
-func (tb *TextBox) getMaxRowSize() int {
-	return tb.rec.Width - 2*(tb.start_pos_x-tb.rec.Start_x)
-}
+	marker_x := tb.rec.Start_x + tb.rec.Width - 3
+	up_marker, down_marker := "─", "─"
+	if tb.view > 0 {
+		up_marker = SCROLL_UP
+	}
 
-func (tb *TextBox) getMaxRows() int {
-	return tb.rec.Height - 2*(tb.start_pos_y-tb.rec.Start_y)
-}
+	if layout[len(layout)-1].Y >= tb.view+tb.getMaxRows() {
+		down_marker = SCROLL_DOWN
+	}
 
-func (tb *TextBox) getPositionInString(x, y int) int {
-	rel_pos_x := x - tb.start_pos_x
-	rel_pos_y := y - tb.start_pos_y
-	return rel_pos_x + rel_pos_y*tb.getMaxRowSize()
+	display.DrawString(screen, up_marker, marker_x, tb.rec.Start_y, styles.BorderStyle)
+	display.DrawString(screen, down_marker, marker_x, tb.rec.Start_y+tb.rec.Height-1, styles.BorderStyle)
 }
 
-func (tb *TextBox) getCurrentPositionInString() int {
-	return tb.getPositionInString(tb.curr_pos_x, tb.curr_pos_y)
+func (tb *TextBox) clearContent(screen tcell.Screen) {
+	for row_idx := 0; row_idx < tb.getMaxRows(); row_idx++ {
+		start_y := tb.start_pos_y + row_idx
+
+		for col_idx := 0; col_idx < tb.getMaxRowSize(); col_idx++ {
+			start_x := tb.start_pos_x + col_idx
+			display.DrawString(screen, " ", start_x, start_y, styles.SimpleStyle)
+		}
+	}
 }
 
-func (tb *TextBox) getLastAvailableStringPosition() (int, int) {
-	content_len := len(tb.content) - 1
-	rel_pos_x := content_len % tb.getMaxRowSize()
-	rel_pos_y := content_len / tb.getMaxRowSize()
+// Returns the style of the content, which turns into a warning
+// when the content is longer than the soft limit
+func (tb *TextBox) getStyle() tcell.Style {
+	if tb.limited && utf8.RuneCountInString(tb.content) > tb.soft_limit {
+		return styles.WarningStyle
+	}
 
-	return rel_pos_x, rel_pos_y
+	return styles.SimpleStyle
 }
 
-func (tb *TextBox) addCharacter(screen tcell.Screen, char rune) {
-	max_row_size := tb.getMaxRowSize() // Get the maximum size of a single row
-	max_nof_line := tb.getMaxRows()    // Get the maximum number of rows for the textbox
-
-	// Check if new characters are allowed
-	if len(tb.content)+1 >= max_nof_line*max_row_size {
+// Displays the title followed, if limited, by the remaining characters
+func (tb *TextBox) displayTitle(screen tcell.Screen) {
+	tb.rec.DrawRectangle(screen) // Clears any previous counter
+	display.DrawString(screen, tb.title, tb.rec.Start_x+3, tb.rec.Start_y, styles.TextBoxTitle)
+	if !tb.limited {
 		return
 	}
 
-	prev_pos_x := tb.curr_pos_x
-	prev_pos_y := tb.curr_pos_y
-	curr_pos := tb.getCurrentPositionInString()
-
-	// Check if the new character can be placed in the same row
-	// or a new row is required to be displayed
-	if len(tb.content[:curr_pos])+1 >= (tb.curr_line+1)*max_row_size {
-		tb.nof_lines++
-		tb.curr_line++
-		tb.curr_pos_x = tb.start_pos_x
-		tb.curr_pos_y = tb.start_pos_y + tb.curr_line
-	} else {
-		tb.curr_pos_x++
+	style := styles.TextBoxTitle
+	if tb.getStyle() == styles.WarningStyle {
+		style = styles.WarningStyle
 	}
 
-	// We need to detect if the add character is an insert
-	// operation or append operation.
-	if curr_pos < len(tb.content) {
-		tb.content = tb.content[:curr_pos] + string(char) + tb.content[curr_pos:]
-
-		// Now, only the portion of the content that has been shifted
-		// needs to be re-displayed as a whole
-		tb.displayContentPortion(screen, prev_pos_x, prev_pos_y, tb.content[curr_pos:])
-	} else {
-		tb.content += string(char)
-		display.DrawString(screen, string(char), prev_pos_x, prev_pos_y, styles.SimpleStyle)
-	}
+	remaining := tb.hard_limit - utf8.RuneCountInString(tb.content)
+	counter := fmt.Sprintf(" (%d left)", remaining)
+	display.DrawString(screen, counter, tb.rec.Start_x+3+len(tb.title), tb.rec.Start_y, style)
+}
 
-	screen.ShowCursor(tb.curr_pos_x, tb.curr_pos_y)
+func (tb *TextBox) getMaxRowSize() int {
+	return tb.rec.Width - 2*(tb.start_pos_x-tb.rec.Start_x)
 }
 
-func (tb *TextBox) handleArrowPressed(screen tcell.Screen, direction Vec2) {
-	abs_pos_x := tb.curr_pos_x - tb.start_pos_x + direction.X
-	new_pos_x := (abs_pos_x) % tb.getMaxRowSize()
-	new_pos_y := tb.curr_pos_y - tb.start_pos_y + direction.Y
-
-	bool2int := map[bool]int{false: 0, true: 1}
-
-	// Check if going left or right we need to switch up or down row
-	if new_pos_x < 0 || abs_pos_x >= tb.getMaxRowSize() {
-		cond1_i := bool2int[new_pos_x < 0]
-		cond2_i := bool2int[abs_pos_x >= tb.getMaxRowSize()]
-		new_pos_y += -1*cond1_i + 1*cond2_i
-		new_pos_x = (tb.getMaxRowSize()-1)*cond1_i + 0*(1-cond1_i)
+func (tb *TextBox) getMaxRows() int {
+	return tb.rec.Height - 2*(tb.start_pos_y-tb.rec.Start_y)
+}
+
+// Moves the cursor on the character with the given index, scrolling the
+// content so that the cursor is always in view
+func (tb *TextBox) moveCursor(screen tcell.Screen, idx int) {
+	tb.curr_idx = max(0, min(utf8.RuneCountInString(tb.content), idx))
+	row := tb.getLayout()[tb.curr_idx].Y
+	if row < tb.view {
+		tb.view = row
+	} else if row >= tb.view+tb.getMaxRows() {
+		tb.view = row - tb.getMaxRows() + 1
+	}
+
+	tb.displayContent(screen)
+	if tb.focus {
+		screen.ShowCursor(tb.GetCursorPosition())
 	}
+}
 
-	// Check out of bound for new y position in the textbox
-	if new_pos_y > tb.getMaxRows() || new_pos_y < 0 {
+func (tb *TextBox) addCharacter(screen tcell.Screen, char rune) {
+	if tb.limited && utf8.RuneCountInString(tb.content)+1 > tb.hard_limit {
 		return
 	}
 
-	// If the new position into the string is grater than the length of the
-	// string, we need to replace the cursor at the last avaiable position
-	new_str_pos := tb.getPositionInString(new_pos_x+tb.start_pos_x, new_pos_y+tb.start_pos_y)
-	if new_str_pos > len(tb.content) {
-		new_pos_x, new_pos_y = tb.getLastAvailableStringPosition()
-		new_pos_x = (new_pos_x + 1) % tb.getMaxRowSize()
+	// The character is inserted where the cursor is
+	content_array := []rune(tb.content)
+	tb.content = string(content_array[:tb.curr_idx]) + string(char) + string(content_array[tb.curr_idx:])
+	tb.moveCursor(screen, tb.curr_idx+1)
+}
 
-		if new_pos_x == 0 {
-			new_pos_y++
-		}
+func (tb *TextBox) handleArrowPressed(screen tcell.Screen, direction Vec2) {
+	if direction.Y == 0 {
+		tb.moveCursor(screen, tb.curr_idx+direction.X)
+		return
 	}
 
-	tb.curr_pos_x = new_pos_x + tb.start_pos_x
-	tb.curr_pos_y = new_pos_y + tb.start_pos_y
-	tb.curr_line = tb.curr_pos_y - tb.start_pos_y
+	// Going up or down the cursor keeps its column, if the row is long
+	// enough, otherwise it goes at the end of the row
+	layout := tb.getLayout()
+	curr_pos := layout[tb.curr_idx]
+	next_idx := -1
+	for idx, pos := range layout {
+		if pos.Y == curr_pos.Y+direction.Y && pos.X <= curr_pos.X {
+			next_idx = idx
+		}
+	}
 
-	screen.ShowCursor(tb.curr_pos_x, tb.curr_pos_y)
+	if next_idx >= 0 {
+		tb.moveCursor(screen, next_idx)
+	}
 }
 
 func (tb *TextBox) handleBackspace(screen tcell.Screen) {
-	// If there is no content return
-	if len(tb.content) < 1 {
+	// Nothing to remove before the first character
+	if tb.curr_idx < 1 {
 		return
 	}
 
-	str_position := tb.getCurrentPositionInString() // Take the position into the string
-	content_array := []rune(tb.content)             // From string to rule array
-	content_len := len(tb.content)                  // Take the length of the string
+	content_array := []rune(tb.content)
+	tb.content = string(content_array[:tb.curr_idx-1]) + string(content_array[tb.curr_idx:])
+	tb.moveCursor(screen, tb.curr_idx-1)
+}
+
+func (tb *TextBox) handleCompletion(screen tcell.Screen) {
+	// When the cycle starts, the current content is the prefix
+	// that all the proposed completions must match
+	if tb.compl_idx < 0 {
+		tb.compl_prefix = tb.content
+	}
+
+	// Look for the next completion matching the prefix
+	for offset := 1; offset <= len(tb.completions); offset++ {
+		idx := (tb.compl_idx + offset) % len(tb.completions)
+		if !strings.HasPrefix(tb.completions[idx], tb.compl_prefix) {
+			continue
+		}
 
-	// Check how many characters are left in the sx substr
-	substr_sx := content_array[0:str_position]
-	if len(substr_sx) < 1 {
+		tb.compl_idx = idx
+		tb.SetContent(screen, tb.completions[idx])
 		return
 	}
+}
+
+// Sets the list of possible completions, cycled by pressing TAB
+func (tb *TextBox) SetCompletions(completions []string) {
+	tb.completions = completions
+	tb.compl_idx = -1
+}
+
+// Replace the whole content of the textbox and moves the cursor at the end.
+// Content longer than the textbox is kept as it is and scrolled.
+func (tb *TextBox) SetContent(screen tcell.Screen, content string) {
+	tb.content = content
+	tb.view = 0
+	tb.moveCursor(screen, utf8.RuneCountInString(content))
+}
 
-	// Otherwise, we need to remove the element
-	str_pos_x, str_pos_y := tb.getLastAvailableStringPosition()
-	remaining_sx := string(substr_sx[0 : str_position-1])
-	remaining_dx := string(substr_sx[str_position:content_len])
-	tb.content = remaining_sx + remaining_dx
-
-	// We need to move the cursor in the correct position
-	next_pos_x := tb.curr_pos_x - 1
-	next_pos_y := tb.curr_pos_y
-
-	if tb.curr_pos_x == tb.start_pos_x {
-		// If the relative position is currently 0
-		next_pos_x = tb.start_pos_x + tb.getMaxRowSize() - 1
-		next_pos_y = next_pos_y - 1
-		tb.curr_line--
-		tb.nof_lines--
+// Lets ENTER break the content into multiple lines
+func (tb *TextBox) SetMultiline(value bool) {
+	tb.multiline = value
+}
+
+// Updates the counter, the style and the highlights of the content after
+// it changed
+func (tb *TextBox) displayLimits(screen tcell.Screen) {
+	if tb.limited || tb.highlight != nil {
+		tb.displayTitle(screen)
+		tb.displayContent(screen)
 	}
+}
 
-	tb.curr_pos_x = next_pos_x
-	tb.curr_pos_y = next_pos_y
-	screen.ShowCursor(tb.curr_pos_x, tb.curr_pos_y)
+// Limits the length of the content. Past the soft limit the content is
+// displayed as a warning, while past the hard limit input is refused.
+func (tb *TextBox) SetLimits(screen tcell.Screen, soft_limit, hard_limit int) {
+	if tb.limited && tb.soft_limit == soft_limit && tb.hard_limit == hard_limit {
+		return
+	}
 
-	// Replace the previous character with a void one
-	display.DrawString(screen, " ", tb.curr_pos_x, tb.curr_pos_y, styles.SimpleStyle)
-	tb.displayContentPortion(screen, tb.curr_pos_x, tb.curr_pos_y, remaining_dx)
+	tb.limited = true
+	tb.soft_limit = soft_limit
+	tb.hard_limit = hard_limit
+	tb.displayTitle(screen)
+	tb.displayContent(screen)
+}
 
-	// Reset the pixed that previously was holding the last character of the string
-	abs_str_pos_x := str_pos_x + tb.start_pos_x
-	abs_str_pos_y := str_pos_y + tb.start_pos_y
-	display.DrawString(screen, " ", abs_str_pos_x, abs_str_pos_y, styles.SimpleStyle)
+// Highlights the parts of the content matching the given expression
+func (tb *TextBox) SetHighlight(screen tcell.Screen, highlight *regexp.Regexp) {
+	tb.highlight = highlight
+	tb.displayContent(screen)
 }
 
 // Check if the textbox collides with input coordinates
@@ -270,7 +360,8 @@ func (tb *TextBox) HasFocus() bool {
 
 // Returns the current cursor position relative to the object
 func (tb *TextBox) GetCursorPosition() (int, int) {
-	return tb.curr_pos_x, tb.curr_pos_y
+	pos := tb.getLayout()[tb.curr_idx]
+	return tb.start_pos_x + pos.X, tb.start_pos_y + pos.Y - tb.view
 }
 
 func (tb *TextBox) GetContent() string {
@@ -278,8 +369,7 @@ func (tb *TextBox) GetContent() string {
 }
 
 func (tb *TextBox) Display(screen tcell.Screen) {
-	tb.rec.DrawRectangle(screen) // Draw the rectangle for the text box
-	display.DrawString(screen, tb.title, tb.rec.Start_x+3, tb.rec.Start_y, styles.TextBoxTitle)
+	tb.displayTitle(screen)   // Draw the rectangle and the title of the text box
 	tb.displayContent(screen) // Display the string content
 }
 
@@ -288,6 +378,11 @@ func (tb *TextBox) HandleEventKey(screen tcell.Screen, event *tcell.EventKey) {
 		return
 	}
 
+	// Any key other than TAB ends the current completion cycle
+	if event.Key() != tcell.KeyTab {
+		tb.compl_idx = -1
+	}
+
 	switch event.Key() {
 	case tcell.KeyEscape:
 		// When Escape is pressed it removes the focus
@@ -295,13 +390,25 @@ func (tb *TextBox) HandleEventKey(screen tcell.Screen, event *tcell.EventKey) {
 		tb.focus = false
 		screen.HideCursor()
 
-	case tcell.KeyEnter: // Not supported yet
-		return
+	case tcell.KeyEnter:
+		// New lines are allowed only in multiline textboxes
+		if !tb.multiline {
+			return
+		}
+
+		tb.addCharacter(screen, '\n')
+		tb.displayLimits(screen)
+
+	case tcell.KeyTab:
+		// When TAB is pressed the content is replaced with the
+		// next completion matching what has been typed so far
+		tb.handleCompletion(screen)
 
 	case tcell.KeyBackspace, tcell.KeyBackspace2:
 		// When backspace is pressed deletes the character where
 		// the cursor is positioned
 		tb.handleBackspace(screen)
+		tb.displayLimits(screen)
 
 	case tcell.KeyUp, tcell.KeyDown, tcell.KeyLeft, tcell.KeyRight:
 		// When the arrow is pressed we need to select the correct
@@ -317,12 +424,13 @@ func (tb *TextBox) HandleEventKey(screen tcell.Screen, event *tcell.EventKey) {
 
 		// Append the pressed letter to the content
 		tb.addCharacter(screen, event.Rune())
+		tb.displayLimits(screen)
 	}
 }
 
 func (tb *TextBox) HandleEventMouse(screen tcell.Screen, event *tcell.EventMouse) {
 	if !tb.focus {
 		tb.focus = true
-		screen.ShowCursor(tb.curr_pos_x, tb.curr_pos_y)
+		screen.ShowCursor(tb.GetCursorPosition())
 	}
 }
@@ -0,0 +1,144 @@
+package objects
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Returns a focused textbox with rows of 6 columns, of which 2 are displayed
+func newTestTextBox(t *testing.T) (*TextBox, tcell.Screen) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(screen.Fini)
+	screen.SetSize(40, 20)
+
+	tb := TextBox_new("T", 0, 0, 10, 5)
+	tb.SetFocus(true)
+	return tb, screen
+}
+
+// Sends the given keys to the textbox, runes as typed characters
+func sendKeys(screen tcell.Screen, tb *TextBox, keys ...any) {
+	for _, key := range keys {
+		switch key := key.(type) {
+		case rune:
+			tb.HandleEventKey(screen, tcell.NewEventKey(tcell.KeyRune, key, tcell.ModNone))
+		case string:
+			for _, char := range key {
+				tb.HandleEventKey(screen, tcell.NewEventKey(tcell.KeyRune, char, tcell.ModNone))
+			}
+		case tcell.Key:
+			tb.HandleEventKey(screen, tcell.NewEventKey(key, 0, tcell.ModNone))
+		}
+	}
+}
+
+func TestGetLayout(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []Vec2
+	}{
+		{"", []Vec2{{0, 0}}},
+		{"ab", []Vec2{{0, 0}, {1, 0}, {2, 0}}},
+		// Rows end at new lines and when the width is full
+		{"a\nb", []Vec2{{0, 0}, {1, 0}, {0, 1}, {1, 1}}},
+		{"abcdefg", []Vec2{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {0, 1}, {1, 1}}},
+		{"abcdef", []Vec2{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {0, 1}}},
+		// Wide characters never span two rows
+		{"abcde✨", []Vec2{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {0, 1}, {2, 1}}},
+	}
+
+	for _, test := range tests {
+		tb := TextBox_new("T", 0, 0, 10, 5)
+		tb.content = test.content
+		if layout := tb.getLayout(); !reflect.DeepEqual(layout, test.want) {
+			t.Errorf("getLayout(%q) = %v, want %v", test.content, layout, test.want)
+		}
+	}
+}
+
+func TestTyping(t *testing.T) {
+	tb, screen := newTestTextBox(t)
+	sendKeys(screen, tb, "ac", tcell.KeyLeft, 'b', tcell.KeyEnter, tcell.KeyRight, "d")
+	if content := tb.GetContent(); content != "abcd" {
+		t.Errorf("content = %q, want %q", content, "abcd")
+	}
+
+	sendKeys(screen, tb, tcell.KeyBackspace2, tcell.KeyBackspace2)
+	if content := tb.GetContent(); content != "ab" {
+		t.Errorf("content after backspaces = %q, want %q", content, "ab")
+	}
+}
+
+func TestMultiline(t *testing.T) {
+	tb, screen := newTestTextBox(t)
+	tb.SetMultiline(true)
+	sendKeys(screen, tb, "abc", tcell.KeyEnter, "d", tcell.KeyUp, 'x')
+	// Going up keeps the column of the cursor
+	if content := tb.GetContent(); content != "axbc\nd" {
+		t.Errorf("content = %q, want %q", content, "axbc\nd")
+	}
+
+	sendKeys(screen, tb, tcell.KeyDown, tcell.KeyBackspace2, tcell.KeyBackspace2)
+	if content := tb.GetContent(); content != "axbc" {
+		t.Errorf("content after backspaces = %q, want %q", content, "axbc")
+	}
+}
+
+func TestSetContent(t *testing.T) {
+	tb, screen := newTestTextBox(t)
+	tb.SetLimits(screen, 4, 5)
+
+	// The content is kept as it is, even past the limits
+	content := "✨ long\nline\nwith runes ü"
+	tb.SetContent(screen, content)
+	if tb.GetContent() != content {
+		t.Errorf("content = %q, want %q", tb.GetContent(), content)
+	}
+
+	// The cursor is at the end, hence the last rows are in view
+	layout := tb.getLayout()
+	if last_row := layout[len(layout)-1].Y; tb.view != last_row-1 {
+		t.Errorf("view = %d, want %d", tb.view, last_row-1)
+	}
+
+	// Whole characters are removed, while no more can be added
+	sendKeys(screen, tb, tcell.KeyBackspace2, 'x')
+	if want := content[:len(content)-len("ü")]; tb.GetContent() != want {
+		t.Errorf("content after backspace = %q, want %q", tb.GetContent(), want)
+	}
+}
+
+func TestScroll(t *testing.T) {
+	tb, screen := newTestTextBox(t)
+	tb.SetMultiline(true)
+	sendKeys(screen, tb, "a", tcell.KeyEnter, "b", tcell.KeyEnter, "c")
+	if tb.view != 1 {
+		t.Errorf("view = %d, want 1", tb.view)
+	}
+
+	if x, y := tb.GetCursorPosition(); x != 3 || y != 3 {
+		t.Errorf("cursor = %d, %d, want 3, 3", x, y)
+	}
+
+	sendKeys(screen, tb, tcell.KeyUp, tcell.KeyUp)
+	if tb.view != 0 {
+		t.Errorf("view after going up = %d, want 0", tb.view)
+	}
+}
+
+func TestGetHighlighted(t *testing.T) {
+	tb := TextBox_new("T", 0, 0, 10, 5)
+	tb.highlight = regexp.MustCompile(`<[^>]+>`)
+	tb.content = "✨ <a> b"
+	want := []bool{false, false, true, true, true, false, false}
+	if highlighted := tb.getHighlighted(); !reflect.DeepEqual(highlighted, want) {
+		t.Errorf("getHighlighted() = %v, want %v", highlighted, want)
+	}
+}
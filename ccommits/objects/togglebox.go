@@ -0,0 +1,120 @@
+package objects
+
+import (
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/display"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/styles"
+)
+
+const CHECKED string = "[x]"
+const UNCHECKED string = "[ ]"
+
+type ToggleBox struct {
+	rec   Rectangle // The rectangle containing the box
+	title string    // The title of the box
+	label string    // The label displayed next to the check mark
+	value bool      // If the toggle is currently checked
+	focus bool      // If the current focus is on this object
+}
+
+func ToggleBox_new(title, label string, x, y, size_w int) *ToggleBox {
+	// The box contains just a single line, i.e., the check mark and the label
+	rect := Rectangle{size_w, 3, x, y}
+	return &ToggleBox{rect, title, label, false, false}
+}
+
+func (tgb *ToggleBox) drawContent(screen tcell.Screen) {
+	mark := UNCHECKED
+	if tgb.value {
+		mark = CHECKED
+	}
+
+	start_x := tgb.rec.Start_x + 2
+	start_y := tgb.rec.Start_y + 1
+	display.DrawString(screen, mark, start_x, start_y, styles.SimpleStyle)
+	display.DrawString(screen, tgb.label, start_x+len(mark)+1, start_y, styles.SimpleStyle)
+}
+
+// Switch the current value of the toggle
+func (tgb *ToggleBox) Toggle(screen tcell.Screen) {
+	tgb.SetChecked(screen, !tgb.value)
+}
+
+// Sets the value of the toggle and updates the check mark
+func (tgb *ToggleBox) SetChecked(screen tcell.Screen, value bool) {
+	tgb.value = value
+	tgb.drawContent(screen)
+}
+
+func (tgb *ToggleBox) IsChecked() bool {
+	return tgb.value
+}
+
+func (tgb *ToggleBox) Display(screen tcell.Screen) {
+	tgb.rec.DrawRectangle(screen) // Draw the rectangle for the toggle box
+	display.DrawString(screen, tgb.title, tgb.rec.Start_x+3, tgb.rec.Start_y, styles.TextBoxTitle)
+	tgb.drawContent(screen)
+}
+
+func (tgb *ToggleBox) IsColliding(x, y int) bool {
+	return ((x >= tgb.rec.Start_x && x <= tgb.rec.Start_x+tgb.rec.Width) &&
+		(y >= tgb.rec.Start_y && y <= tgb.rec.Start_y+tgb.rec.Height))
+}
+
+func (tgb *ToggleBox) SetFocus(value bool) {
+	tgb.focus = value
+}
+
+func (tgb *ToggleBox) HasFocus() bool {
+	return tgb.focus
+}
+
+// Returns the current cursor position relative to the object
+func (tgb *ToggleBox) GetCursorPosition() (int, int) {
+	return tgb.rec.Start_x + 3, tgb.rec.Start_y + 1
+}
+
+func (tgb *ToggleBox) GetContent() string {
+	return strconv.FormatBool(tgb.value)
+}
+
+func (tgb *ToggleBox) HandleEventKey(screen tcell.Screen, event *tcell.EventKey) {
+	if !tgb.focus {
+		return
+	}
+
+	switch event.Key() {
+	case tcell.KeyEscape:
+		// When Escape is pressed it removes the focus
+		// from the current object
+		tgb.focus = false
+		screen.HideCursor()
+
+	case tcell.KeyEnter:
+		// Enter switches the value of the toggle
+		tgb.Toggle(screen)
+
+	case tcell.KeyRune:
+		// As well as the space bar
+		if event.Rune() == ' ' {
+			tgb.Toggle(screen)
+		}
+
+	default:
+		return
+	}
+}
+
+func (tgb *ToggleBox) HandleEventMouse(screen tcell.Screen, event *tcell.EventMouse) {
+	if !tgb.focus {
+		tgb.focus = true
+		screen.ShowCursor(tgb.GetCursorPosition())
+	}
+
+	// A real click, not just a focus change, switches the value
+	if event != nil {
+		tgb.Toggle(screen)
+	}
+}
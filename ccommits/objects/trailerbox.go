@@ -0,0 +1,305 @@
+package objects
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/display"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+const PROMPT string = "> "
+
+// A trailer key must be a single token made of letters, digits and dashes
+var TRAILER_KEY_RE = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
+type KeyValue struct {
+	Key       string // The key of the row
+	Separator string // Either ": " or " #", as in "Refs #12"
+	Value     string // The value of the row
+}
+
+type TrailerBox struct {
+	rec      Rectangle  // The rectangle containing the box
+	title    string     // The title of the box
+	rows     []KeyValue // All the rows already inserted
+	keys     []string   // The known keys, cycled by pressing TAB
+	input    string     // The row currently being written
+	curr_idx int        // The index of the selected row (-1 if none)
+	focus    bool       // If the current focus is on this object
+
+	compl_prefix string // The input typed before cycling through keys
+	compl_idx    int    // Index of the current key completion (-1 if none)
+}
+
+func TrailerBox_new(title string, x, y, size_w, size_h int, keys []string) *TrailerBox {
+	// First, creates the rectangle
+	rect := Rectangle{size_w, size_h + 1, x, y}
+
+	tb := new(TrailerBox)
+	tb.rec = rect
+	tb.title = title
+	tb.rows = make([]KeyValue, 0)
+	tb.keys = keys
+	tb.input = ""
+	tb.curr_idx = -1
+	tb.focus = false
+	tb.compl_prefix = ""
+	tb.compl_idx = -1
+
+	return tb
+}
+
+func (trb *TrailerBox) getMaxNofLines() int {
+	return trb.rec.Height - 5
+}
+
+func (trb *TrailerBox) getMaxRowSize() int {
+	return trb.rec.Width - 4
+}
+
+func (trb *TrailerBox) getInputPosition() (int, int) {
+	return trb.rec.Start_x + 2, trb.rec.Start_y + trb.rec.Height - 2
+}
+
+// Returns the index of the first row displayed in the box
+func (trb *TrailerBox) getFirstVisibleRow() int {
+	// The view scrolls only when the selected row would be hidden
+	max_lines := trb.getMaxNofLines()
+	if trb.curr_idx < max_lines {
+		return 0
+	}
+
+	return trb.curr_idx - max_lines + 1
+}
+
+func (trb *TrailerBox) clearContent(screen tcell.Screen) {
+	for idx := 0; idx < trb.rec.Height-2; idx++ {
+		start_y := trb.rec.Start_y + idx + 1
+
+		for row_idx := 0; row_idx < trb.getMaxRowSize(); row_idx++ {
+			start_x := trb.rec.Start_x + row_idx + 2
+			display.DrawString(screen, " ", start_x, start_y, styles.SimpleStyle)
+		}
+	}
+}
+
+func (trb *TrailerBox) drawContent(screen tcell.Screen) {
+	trb.clearContent(screen)
+
+	// Draw all the visible rows, highlighting the selected one
+	start_x := trb.rec.Start_x + 2
+	first_row := trb.getFirstVisibleRow()
+	last_row := min(len(trb.rows), first_row+trb.getMaxNofLines())
+	for idx := first_row; idx < last_row; idx++ {
+		style := styles.SimpleStyle
+		if idx == trb.curr_idx && trb.focus {
+			style = styles.SelectStyle
+		}
+
+		str_content := FormatKeyValue(trb.rows[idx])
+		str_content = runewidth.Truncate(str_content, trb.getMaxRowSize(), "")
+		start_y := trb.rec.Start_y + 2 + (idx - first_row)
+		display.DrawString(screen, str_content, start_x, start_y, style)
+	}
+
+	// Draw the row currently being written. When it is too long
+	// only the last part, where the cursor is, is displayed.
+	input := PROMPT + trb.input
+	input_width := runewidth.StringWidth(input)
+	if input_width > trb.getMaxRowSize()-1 {
+		input = runewidth.TruncateLeft(input, input_width-trb.getMaxRowSize()+1, "")
+	}
+
+	input_x, input_y := trb.getInputPosition()
+	display.DrawString(screen, input, input_x, input_y, styles.SimpleStyle)
+}
+
+func (trb *TrailerBox) handleCompletion(screen tcell.Screen) {
+	// Only the key can be completed, i.e., before the separator
+	if strings.Contains(trb.input, ":") || len(trb.keys) < 1 {
+		return
+	}
+
+	if trb.compl_idx < 0 {
+		trb.compl_prefix = strings.ToLower(trb.input)
+	}
+
+	// Look for the next key matching the prefix
+	for offset := 1; offset <= len(trb.keys); offset++ {
+		idx := (trb.compl_idx + offset) % len(trb.keys)
+		if !strings.HasPrefix(strings.ToLower(trb.keys[idx]), trb.compl_prefix) {
+			continue
+		}
+
+		trb.compl_idx = idx
+		trb.input = trb.keys[idx]
+		trb.drawContent(screen)
+		return
+	}
+}
+
+func (trb *TrailerBox) handleEnter(screen tcell.Screen) {
+	// The input must be in the form Key: value
+	parts := strings.SplitN(trb.input, ":", 2)
+	if len(parts) < 2 {
+		return
+	}
+
+	key := strings.TrimSpace(parts[0])
+	value := strings.TrimSpace(parts[1])
+	if !TRAILER_KEY_RE.MatchString(key) || len(value) < 1 {
+		return
+	}
+
+	trb.rows = append(trb.rows, KeyValue{key, ": ", value})
+	trb.curr_idx = len(trb.rows) - 1
+	trb.input = ""
+	trb.drawContent(screen)
+}
+
+func (trb *TrailerBox) handleDelete(screen tcell.Screen) {
+	if trb.curr_idx < 0 {
+		return
+	}
+
+	trb.rows = append(trb.rows[:trb.curr_idx], trb.rows[trb.curr_idx+1:]...)
+	trb.curr_idx = min(trb.curr_idx, len(trb.rows)-1)
+	trb.drawContent(screen)
+}
+
+func (trb *TrailerBox) handleArrowPressed(screen tcell.Screen, direction int) {
+	next_idx := trb.curr_idx + direction
+	if next_idx < 0 || next_idx >= len(trb.rows) {
+		return
+	}
+
+	trb.curr_idx = next_idx
+	trb.drawContent(screen)
+}
+
+// Returns all the rows inserted into the box
+func (trb *TrailerBox) GetRows() []KeyValue {
+	return trb.rows
+}
+
+// Replace all the rows of the box
+func (trb *TrailerBox) SetRows(screen tcell.Screen, rows []KeyValue) {
+	trb.rows = append(make([]KeyValue, 0, len(rows)), rows...)
+	trb.curr_idx = len(trb.rows) - 1
+	trb.drawContent(screen)
+}
+
+func (trb *TrailerBox) Display(screen tcell.Screen) {
+	trb.rec.DrawRectangle(screen) // Draw the rectangle for the box
+	display.DrawString(screen, trb.title, trb.rec.Start_x+3, trb.rec.Start_y, styles.TextBoxTitle)
+	trb.drawContent(screen)
+}
+
+func (trb *TrailerBox) IsColliding(x, y int) bool {
+	return ((x >= trb.rec.Start_x && x <= trb.rec.Start_x+trb.rec.Width) &&
+		(y >= trb.rec.Start_y && y <= trb.rec.Start_y+trb.rec.Height))
+}
+
+func (trb *TrailerBox) SetFocus(value bool) {
+	trb.focus = value
+	trb.compl_idx = -1
+}
+
+func (trb *TrailerBox) HasFocus() bool {
+	return trb.focus
+}
+
+// Returns the current cursor position relative to the object
+func (trb *TrailerBox) GetCursorPosition() (int, int) {
+	input_x, input_y := trb.getInputPosition()
+	input_width := min(runewidth.StringWidth(PROMPT+trb.input), trb.getMaxRowSize()-1)
+	return input_x + input_width, input_y
+}
+
+// Returns all the rows, one per line, in the Key: value format
+func (trb *TrailerBox) GetContent() string {
+	lines := make([]string, 0, len(trb.rows))
+	for _, row := range trb.rows {
+		lines = append(lines, FormatKeyValue(row))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (trb *TrailerBox) HandleEventKey(screen tcell.Screen, event *tcell.EventKey) {
+	if !trb.focus {
+		return
+	}
+
+	// Any key other than TAB ends the current completion cycle
+	if event.Key() != tcell.KeyTab {
+		trb.compl_idx = -1
+	}
+
+	switch event.Key() {
+	case tcell.KeyEscape:
+		// When Escape is pressed it removes the focus
+		// from the current object
+		trb.focus = false
+		trb.drawContent(screen)
+		screen.HideCursor()
+		return
+
+	case tcell.KeyEnter:
+		// Enter adds the written row to the list
+		trb.handleEnter(screen)
+
+	case tcell.KeyTab:
+		// TAB completes the key with the next known one
+		trb.handleCompletion(screen)
+
+	case tcell.KeyDelete:
+		// Delete removes the selected row
+		trb.handleDelete(screen)
+
+	case tcell.KeyUp, tcell.KeyDown:
+		// Arrows move the selection between rows
+		trb.handleArrowPressed(screen, DIRECTIONS[event.Key()])
+
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		// Backspace removes the last character of the input
+		if len(trb.input) < 1 {
+			return
+		}
+
+		input := []rune(trb.input)
+		trb.input = string(input[:len(input)-1])
+		trb.drawContent(screen)
+
+	default:
+		// Otherwise, check if the key pressed is a letter
+		if event.Rune() == 0 {
+			return
+		}
+
+		trb.input += string(event.Rune())
+		trb.drawContent(screen)
+	}
+
+	screen.ShowCursor(trb.GetCursorPosition())
+}
+
+func (trb *TrailerBox) HandleEventMouse(screen tcell.Screen, event *tcell.EventMouse) {
+	if !trb.focus {
+		trb.focus = true
+		trb.drawContent(screen)
+		screen.ShowCursor(trb.GetCursorPosition())
+	}
+}
+
+// Format the row as Key: value, or Key #value for references
+func FormatKeyValue(row KeyValue) string {
+	if row.Separator == " #" {
+		return row.Key + row.Separator + row.Value
+	}
+
+	return row.Key + ": " + row.Value
+}
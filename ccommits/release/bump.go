@@ -0,0 +1,61 @@
+package release
+
+import (
+	"strings"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/lint"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+type BumpCommit struct {
+	Sha    string `json:"sha"`    // The abbreviated hash of the commit
+	Header string `json:"header"` // The first line of the message
+	Bump   Bump   `json:"bump"`   // How much the commit increases the version
+}
+
+type NextVersion struct {
+	Tag     string       `json:"tag"`     // The tag of the latest version (empty if none)
+	Current Version      `json:"current"` // The latest released version
+	Next    Version      `json:"next"`    // The version including the commits
+	Bump    Bump         `json:"bump"`    // The largest bump among the commits
+	Commits []BumpCommit `json:"commits"` // The commits increasing the version
+}
+
+// Returns how much the message increases the version. Breaking changes are
+// always major, while the bump of the other commits depends on the type.
+func GetBump(msg *message.Message, rules map[string]Bump) Bump {
+	if msg.IsBreaking() {
+		return MAJOR
+	}
+
+	return rules[strings.ToUpper(msg.Type)]
+}
+
+// Computes the version following the one of the given tag, given the commits
+// made since then. Commits that do not follow the format do not count.
+func GetNextVersion(current Version, tag string, commits []util.Commit, rules map[string]Bump) NextVersion {
+	next := NextVersion{tag, current, current, NONE, make([]BumpCommit, 0)}
+	for _, commit := range commits {
+		if lint.IsIgnored(commit.Message) {
+			continue
+		}
+
+		msg, err := message.Parse(commit.Message)
+		if err != nil {
+			continue
+		}
+
+		bump := GetBump(msg, rules)
+		if bump == NONE {
+			continue
+		}
+
+		next.Bump = max(next.Bump, bump)
+		next.Commits = append(next.Commits,
+			BumpCommit{commit.GetShortSha(), msg.Header(), bump})
+	}
+
+	next.Next = current.Increase(next.Bump)
+	return next
+}
@@ -0,0 +1,127 @@
+/*
+This package computes the next semantic version from the conventional
+commits of the history and prepares the releases.
+*/
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// A version tag, e.g., v1.2.3. Pre-releases and builds are not considered.
+var VERSION_RE = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)$`)
+
+// The prefix of the tags when the repository has none yet
+const DEFAULT_PREFIX string = "v"
+
+// How much a version is increased, from the smallest to the largest
+type Bump int
+
+const (
+	NONE  Bump = 0
+	PATCH Bump = 1
+	MINOR Bump = 2
+	MAJOR Bump = 3
+)
+
+type Version struct {
+	Prefix string // Either v or empty, as written in the tag
+	Major  int    // Increased for breaking changes
+	Minor  int    // Increased for new features
+	Patch  int    // Increased for fixes
+}
+
+// Returns the bump with the given name, i.e., none, patch, minor or major
+func ParseBump(name string) (Bump, error) {
+	for bump := NONE; bump <= MAJOR; bump++ {
+		if bump.String() == name {
+			return bump, nil
+		}
+	}
+
+	return NONE, fmt.Errorf("bump %q must be one of none, patch, minor or major", name)
+}
+
+func (b Bump) String() string {
+	switch b {
+	case PATCH:
+		return "patch"
+	case MINOR:
+		return "minor"
+	case MAJOR:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// Writes the bump by name in the JSON output
+func (b Bump) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// Parses a version tag, returning false if the tag is not a version
+func ParseVersion(tag string) (Version, bool) {
+	matches := VERSION_RE.FindStringSubmatch(tag)
+	if matches == nil {
+		return Version{}, false
+	}
+
+	major, _ := strconv.Atoi(matches[2])
+	minor, _ := strconv.Atoi(matches[3])
+	patch, _ := strconv.Atoi(matches[4])
+	return Version{matches[1], major, minor, patch}, true
+}
+
+// Returns the highest version among the given tags, with its tag. When
+// none of them is a version, 0.0.0 is returned with an empty tag.
+func GetLatestVersion(tags []string) (Version, string) {
+	latest, latest_tag := Version{DEFAULT_PREFIX, 0, 0, 0}, ""
+	for _, tag := range tags {
+		version, ok := ParseVersion(tag)
+		if ok && (len(latest_tag) < 1 || latest.Less(version)) {
+			latest, latest_tag = version, tag
+		}
+	}
+
+	return latest, latest_tag
+}
+
+// Returns true if the version precedes the other one
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+
+	return v.Patch < other.Patch
+}
+
+// Returns the version increased by the given bump
+func (v Version) Increase(bump Bump) Version {
+	switch bump {
+	case MAJOR:
+		return Version{v.Prefix, v.Major + 1, 0, 0}
+	case MINOR:
+		return Version{v.Prefix, v.Major, v.Minor + 1, 0}
+	case PATCH:
+		return Version{v.Prefix, v.Major, v.Minor, v.Patch + 1}
+	default:
+		return v
+	}
+}
+
+// Writes the version as a string in the JSON output
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// Returns the version as written in the tag
+func (v Version) String() string {
+	return fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+}
@@ -0,0 +1,126 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
+)
+
+var RULES = map[string]Bump{"FEAT": MINOR, "FIX": PATCH, "PERF": PATCH}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Version
+		ok   bool
+	}{
+		{"v1.2.3", Version{"v", 1, 2, 3}, true},
+		{"1.2.3", Version{"", 1, 2, 3}, true},
+		{"v10.0.12", Version{"v", 10, 0, 12}, true},
+		{"v1.2", Version{}, false},
+		{"v1.2.3-rc.1", Version{}, false},
+		{"release-1.2.3", Version{}, false},
+	}
+
+	for _, test := range tests {
+		version, ok := ParseVersion(test.tag)
+		if version != test.want || ok != test.ok {
+			t.Errorf("ParseVersion(%q) = %v, %t, want %v, %t", test.tag, version, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestGetLatestVersion(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want string
+		tag  string
+	}{
+		{[]string{}, "v0.0.0", ""},
+		{[]string{"nightly", "v1.0.0-rc.1"}, "v0.0.0", ""},
+		{[]string{"v1.2.0", "v1.10.0", "v1.9.9"}, "v1.10.0", "v1.10.0"},
+		{[]string{"0.9.0", "1.0.0"}, "1.0.0", "1.0.0"},
+	}
+
+	for _, test := range tests {
+		version, tag := GetLatestVersion(test.tags)
+		if version.String() != test.want || tag != test.tag {
+			t.Errorf("GetLatestVersion(%v) = %s, %q, want %s, %q", test.tags, version, tag, test.want, test.tag)
+		}
+	}
+}
+
+func TestIncrease(t *testing.T) {
+	version := Version{"v", 1, 2, 3}
+	tests := map[Bump]string{NONE: "v1.2.3", PATCH: "v1.2.4", MINOR: "v1.3.0", MAJOR: "v2.0.0"}
+	for bump, want := range tests {
+		if increased := version.Increase(bump).String(); increased != want {
+			t.Errorf("Increase(%s) = %s, want %s", bump, increased, want)
+		}
+	}
+}
+
+func TestGetBump(t *testing.T) {
+	tests := []struct {
+		content string
+		want    Bump
+	}{
+		{"feat: add", MINOR},
+		{"fix(api): close", PATCH},
+		{"Fix: close", PATCH},
+		{"docs: readme", NONE},
+		{"docs!: drop the old guide", MAJOR},
+		{"fix: close\n\nBREAKING-CHANGE: the file is closed", MAJOR},
+	}
+
+	for _, test := range tests {
+		msg, err := message.Parse(test.content)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bump := GetBump(msg, RULES); bump != test.want {
+			t.Errorf("GetBump(%q) = %s, want %s", test.content, bump, test.want)
+		}
+	}
+}
+
+func TestGetNextVersion(t *testing.T) {
+	tests := []struct {
+		messages []string
+		want     string
+		nof_bump int
+	}{
+		{[]string{}, "v1.2.3", 0},
+		{[]string{"docs: readme", "not conventional"}, "v1.2.3", 0},
+		{[]string{"fix: a", "docs: b"}, "v1.2.4", 1},
+		{[]string{"fix: a", "feat: b", "perf: c"}, "v1.3.0", 3},
+		{[]string{"feat: a", "refactor!: b", "Revert \"feat!: c\""}, "v2.0.0", 2},
+	}
+
+	for _, test := range tests {
+		commits := make([]util.Commit, 0, len(test.messages))
+		for _, content := range test.messages {
+			commits = append(commits, util.Commit{Sha: "1234567890", Message: content})
+		}
+
+		next := GetNextVersion(Version{"v", 1, 2, 3}, "v1.2.3", commits, RULES)
+		if next.Next.String() != test.want || len(next.Commits) != test.nof_bump {
+			t.Errorf("GetNextVersion(%v) = %s with %d commits, want %s with %d",
+				test.messages, next.Next, len(next.Commits), test.want, test.nof_bump)
+		}
+	}
+}
+
+func TestParseBump(t *testing.T) {
+	for _, name := range []string{"none", "patch", "minor", "major"} {
+		if bump, err := ParseBump(name); err != nil || bump.String() != name {
+			t.Errorf("ParseBump(%q) = %s, %v", name, bump, err)
+		}
+	}
+
+	if _, err := ParseBump("huge"); err == nil {
+		t.Errorf("ParseBump(\"huge\") succeeded, want an error")
+	}
+}
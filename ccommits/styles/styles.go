@@ -6,12 +6,14 @@ package styles
 import "github.com/gdamore/tcell/v2"
 
 var (
-	SimpleStyle   = tcell.StyleDefault.Foreground(tcell.ColorWhite)
-	SelectStyle   = tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGray).Underline(true)
-	BorderStyle   = tcell.StyleDefault.Foreground(tcell.ColorDarkSlateGray)
-	TextBoxTitle  = tcell.StyleDefault.Foreground(tcell.ColorCadetBlue).Italic(true).Underline(true)
-	ArrowDown     = tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkSlateGray)
-	TitleStyle    = tcell.StyleDefault.Foreground(tcell.ColorDarkOrange).Bold(true).Underline(true)
-	SubTitleStyle = tcell.StyleDefault.Foreground(tcell.ColorDarkSlateBlue).Bold(true).Italic(true)
-	GitInfoStyle  = tcell.StyleDefault.Foreground(tcell.ColorMediumVioletRed).Underline(true)
+	SimpleStyle      = tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	SelectStyle      = tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGray).Underline(true)
+	BorderStyle      = tcell.StyleDefault.Foreground(tcell.ColorDarkSlateGray)
+	TextBoxTitle     = tcell.StyleDefault.Foreground(tcell.ColorCadetBlue).Italic(true).Underline(true)
+	ArrowDown        = tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkSlateGray)
+	TitleStyle       = tcell.StyleDefault.Foreground(tcell.ColorDarkOrange).Bold(true).Underline(true)
+	SubTitleStyle    = tcell.StyleDefault.Foreground(tcell.ColorDarkSlateBlue).Bold(true).Italic(true)
+	GitInfoStyle     = tcell.StyleDefault.Foreground(tcell.ColorMediumVioletRed).Underline(true)
+	WarningStyle     = tcell.StyleDefault.Foreground(tcell.ColorOrange)
+	PlaceholderStyle = tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorGold)
 )
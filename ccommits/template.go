@@ -0,0 +1,52 @@
+package ccommits
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
+)
+
+// A placeholder of a template, e.g., {{package}}, to be replaced
+var PLACEHOLDER_RE = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// A template prefilling the composer with a kind of commit written again
+// and again. Placeholders are highlighted until they are replaced.
+type Template struct {
+	Type    string `json:"type"`    // The type of the change
+	Scope   string `json:"scope"`   // The scope of the change (Optional)
+	Emoji   string `json:"emoji"`   // The gitmoji, either unicode or shortcode (Optional)
+	Subject string `json:"subject"` // The short description (Optional)
+	Body    string `json:"body"`    // The skeleton of the longer description (Optional)
+}
+
+// Checks that the type and the gitmoji of the template are allowed
+func (t Template) validate(name string, types map[string]string, gitmojis []Gitmoji) error {
+	if _, ok := types[strings.ToUpper(t.Type)]; !ok {
+		return fmt.Errorf("type %q of template %q is not an allowed type", t.Type, name)
+	}
+
+	if _, ok := FindGitmoji(gitmojis, t.Emoji); len(t.Emoji) > 0 && !ok {
+		return fmt.Errorf("gitmoji %q of template %q is not a known gitmoji", t.Emoji, name)
+	}
+
+	return nil
+}
+
+// Returns the message the composer is filled with
+func (t Template) GetMessage() *message.Message {
+	return &message.Message{Type: t.Type, Scope: t.Scope, Emoji: t.Emoji,
+		Subject: t.Subject, Body: t.Body}
+}
+
+// Returns all the placeholders of the template
+func (t Template) GetPlaceholders() []string {
+	content := strings.Join([]string{t.Scope, t.Subject, t.Body}, "\n")
+	return PLACEHOLDER_RE.FindAllString(content, -1)
+}
+
+// Returns the header of the template, shown when picking it
+func (t Template) String() string {
+	return t.GetMessage().Header()
+}
@@ -6,22 +6,30 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 const separator string = string(os.PathSeparator)
 
 type GitInfo struct {
-	Reponame    string   // The name of the current repository
-	Branches    []string // All the branches for the current repository
-	Remotes     []string // All remotes for the current repository
-	Curr_branch string   // The current branch name
-	Curr_remote string   // The remote for the current branch
-	Commit_str  string   // The commit message string
-	PrevContent string   // The previous content of the .git file (only for worktrees)
-	GitDir      string   // The root folder of git
-	TargetPath  string   // The target path of all git commands
-	User        string   // The user specified in the config file
+	Reponame    string     // The name of the current repository
+	Branches    []string   // All the branches for the current repository
+	Remotes     []string   // All remotes for the current repository
+	Curr_branch string     // The current branch name
+	Curr_remote string     // The remote for the current branch
+	Commit_str  string     // The commit message string
+	PrevContent string     // The previous content of the .git file (only for worktrees)
+	GitDir      string     // The root folder of git
+	BranchDir   string     // The git folder of the current worktree
+	CommonDir   string     // The git folder shared by all worktrees
+	TargetPath  string     // The target path of all git commands
+	User        string     // The user specified in the config file
+	Config      *GitConfig // The git configuration of the repository
+	Amend       bool       // If the last commit is amended instead of creating a new one
 }
 
 func extractRepoName(url string) string {
@@ -39,34 +47,30 @@ func extractRepoName(url string) string {
 
 	// Split the string by the slash and take the last part
 	parts = strings.Split(url, "/")
+	if len(parts) < 2 {
+		return url
+	}
+
 	sig_parts := []string{parts[len(parts)-2], parts[len(parts)-1]}
 	return strings.Join(sig_parts, "/")
 }
 
-// Returns the name of the repository
-func getRepositoryName(gitdir string) (string, error) {
-	// The name of the repository can be found in the config file
-	// Format the file path and reads its content, if any error occurs
-	// then it will returns an empty string as long with the error
-	config_file := filepath.Join(gitdir, "config")
-	data, err := os.ReadFile(config_file)
-	if err != nil {
-		return "", err
+// Returns the name of the repository, taken from the URL of the preferred
+// remote. Repositories without any remote URL are named after their folder.
+func getRepositoryName(rootpath string, remotes []Remote) string {
+	for _, remote := range remotes {
+		if remote.Name == DEFAULT_REMOTE && len(remote.Urls) > 0 {
+			return extractRepoName(remote.Urls[0])
+		}
 	}
 
-	// Parse the config file to find the repository name.
-	// Usually, it is indicated into the remote url
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		if !strings.Contains(line, "url =") {
-			continue
+	for _, remote := range remotes {
+		if len(remote.Urls) > 0 {
+			return extractRepoName(remote.Urls[0])
 		}
-
-		url := strings.TrimSpace(strings.Split(line, "=")[1])
-		return extractRepoName(url), nil
 	}
 
-	return "", err
+	return filepath.Base(rootpath)
 }
 
 // Returns the name of all branches
@@ -133,29 +137,14 @@ func getCurrentBranch(gitdir string) (string, error) {
 	return strings.Join(parts[head_idx+1:], "/"), nil
 }
 
-func getAllRemotes(gitdir string) ([]string, error) {
-	config_file := filepath.Join(gitdir, "config")
-	data, err := os.ReadFile(config_file)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse the config file to find the repository name.
-	// Usually, it is indicated into the remote url
-	lines := strings.Split(string(data), "\n")
-	remotes := make([]string, 0)
-	for _, line := range lines {
-		if !strings.HasPrefix(line, "[remote ") {
-			continue
-		}
-
-		start_idx := len("[remote ")
-		end_idx := len(line) - 1
-		remote := line[start_idx+1 : end_idx-1]
-		remotes = append(remotes, remote)
+// Returns the name of all remotes
+func getAllRemotes(remotes []Remote) []string {
+	names := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		names = append(names, remote.Name)
 	}
 
-	return remotes, nil
+	return names
 }
 
 func getGitInfo(rootpath, srcpath, entrypath string) *GitInfo {
@@ -214,24 +203,25 @@ func getGitInfo(rootpath, srcpath, entrypath string) *GitInfo {
 		git_dir = strings.Join([]string{branch_dir, common_dir_str}, separator)
 	}
 
-	fmt.Println("Runnig command")
-	config_cmd := exec.Command("git", "config", "--global", "--add", "safe.directory", rootpath)
-	config_cmd.Stdout = os.Stdout
-	config_cmd.Stderr = os.Stderr
+	gitinfo.BranchDir = filepath.Clean(branch_dir) // Set the worktree git folder
+	gitinfo.CommonDir = filepath.Clean(git_dir)    // Set the shared git folder
 
-	err = config_cmd.Run()
-	if err != nil {
-		fmt.Println(err.Error())
+	// Inside a container the repository usually belongs to another user,
+	// hence git refuses to work with it unless it is marked as safe
+	if is_container, _, _ := IsContainerEnvironment(); is_container {
+		trustRepository(rootpath)
 	}
 
-	// Get the repository name
-	repo_name, err := getRepositoryName(git_dir)
+	// Read the configuration, as git does, to find the remotes and the user
+	config, err := ReadGitConfig(rootpath)
 	if err != nil {
 		fmt.Printf("An Error occurred: %s\n", err)
 		return nil
 	}
 
-	gitinfo.Reponame = repo_name // Set the repository name
+	gitinfo.Config = config
+	gitinfo.User, _ = config.Get("user.name")
+	gitinfo.Reponame = getRepositoryName(rootpath, config.GetRemotes()) // Set the repository name
 
 	// Get all the branches from the .git/refs/head/ folder
 	branches, err := getAllBranches(filepath.Join(git_dir, "refs", "heads"), 0)
@@ -252,13 +242,113 @@ func getGitInfo(rootpath, srcpath, entrypath string) *GitInfo {
 	gitinfo.Curr_branch = branch_name // Set the branch name
 
 	// Get all remotes
-	remotes, err := getAllRemotes(git_dir)
+	gitinfo.Remotes = getAllRemotes(config.GetRemotes()) // Set the remotes to the info structure
+
+	return gitinfo
+}
+
+// Returns the root folder of the repository containing the given folder
+func FindRepositoryRoot(folder string) (string, error) {
+	// Go up in the folder hierarchy until the .git folder (or file) is found
+	curr_folder := filepath.Clean(folder)
+	for {
+		if _, err := os.Stat(filepath.Join(curr_folder, ".git")); err == nil {
+			return curr_folder, nil
+		}
+
+		parent := filepath.Dir(curr_folder)
+		if parent == curr_folder {
+			return "", fmt.Errorf("%s does not belong to a repository", folder)
+		}
+
+		curr_folder = parent
+	}
+}
+
+// Marks the repository as safe for all the git commands run by ccommits.
+// The setting is passed through the environment, as with git -c, so that
+// the global configuration of the user is left untouched. Repositories
+// already marked are skipped.
+func trustRepository(rootpath string) {
+	count, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	for idx := range count {
+		if os.Getenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", idx)) == "safe.directory" &&
+			os.Getenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", idx)) == rootpath {
+			return
+		}
+	}
+
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", count), "safe.directory")
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", count), rootpath)
+	os.Setenv("GIT_CONFIG_COUNT", strconv.Itoa(count+1))
+}
+
+// Returns the environment passing the given setting to git, as git -c does,
+// after the settings already passed to all the git commands of ccommits
+func GetConfigEnv(key, value string) []string {
+	count, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	return []string{
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", count, key),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", count, value),
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", count+1),
+	}
+}
+
+// Runs the given git command in the folder and returns its trimmed output
+func getGitOutput(folder string, args ...string) (string, error) {
+	gitcmd := exec.Command("git", args...)
+	gitcmd.Dir = folder
+	var out bytes.Buffer
+	gitcmd.Stdout = &out
+	if err := gitcmd.Run(); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Returns the git information of the repository containing the given folder,
+// as resolved by git itself. Unlike GetGitRepositoryInformation nothing is
+// printed or changed, no remote is selected and no check is performed on the
+// changes to commit. Hence, it is the one used by the sub-commands.
+func GetGitInfo(folder string) *GitInfo {
+	// As for the composer, inside a container the repository must be marked
+	// as safe, otherwise git refuses to even look it up
+	if is_container, _, _ := IsContainerEnvironment(); is_container {
+		if rootpath, err := FindRepositoryRoot(folder); err == nil {
+			trustRepository(rootpath)
+		}
+	}
+
+	out, err := getGitOutput(folder, "rev-parse", "--path-format=absolute",
+		"--show-toplevel", "--git-dir", "--git-common-dir")
+	dirs := strings.Split(out, "\n")
+	if err != nil || len(dirs) < 3 {
+		fmt.Printf("An Error occurred: %s does not belong to a repository\n", folder)
+		return nil
+	}
+
+	gitinfo := new(GitInfo)
+	gitinfo.TargetPath = dirs[0]
+	gitinfo.GitDir = filepath.Join(dirs[0], ".git")
+	gitinfo.BranchDir = filepath.Clean(dirs[1])
+	gitinfo.CommonDir = filepath.Clean(dirs[2])
+
+	config, err := ReadGitConfig(gitinfo.TargetPath)
 	if err != nil {
 		fmt.Printf("An Error occurred: %s\n", err)
 		return nil
 	}
 
-	gitinfo.Remotes = remotes // Set the remotes to the info structure
+	gitinfo.Config = config
+	gitinfo.User, _ = config.Get("user.name")
+	gitinfo.Reponame = getRepositoryName(gitinfo.TargetPath, config.GetRemotes())
+	gitinfo.Remotes = getAllRemotes(config.GetRemotes())
+
+	// The current branch is empty when the HEAD is detached
+	branches, _ := getGitOutput(gitinfo.TargetPath, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	gitinfo.Branches = strings.Fields(branches)
+	gitinfo.Curr_branch, _ = getGitOutput(gitinfo.TargetPath, "symbolic-ref", "--quiet", "--short", "HEAD")
 
 	return gitinfo
 }
@@ -284,7 +374,7 @@ func checkChangesToCommit(gi *GitInfo) {
 	fmt.Println()
 }
 
-func GetGitRepositoryInformation(remote_name, tgfolder, srcfolder, entrypath string) *GitInfo {
+func GetGitRepositoryInformation(tgfolder, srcfolder, entrypath string, check_changes bool) *GitInfo {
 	fmt.Println("------------------------- GIT REPOSITORY GATHERING -------------------------")
 
 	gitinfo := getGitInfo(tgfolder, srcfolder, entrypath)
@@ -298,56 +388,89 @@ func GetGitRepositoryInformation(remote_name, tgfolder, srcfolder, entrypath str
 	fmt.Printf("DETECTED REPOSITORY BRANCHES: \033[3m%s\033[0m\n", strings.Join(gitinfo.Branches, ", "))
 	fmt.Printf("DETECTED POSSIBLE REMOTES: \033[3m%s\033[0m\n", strings.Join(gitinfo.Remotes, ", "))
 
-	if len(remote_name) < 1 && len(gitinfo.Remotes) > 1 {
-		fmt.Print("\n[*] Please Choose a remote: ")
-		fmt.Scanln(&gitinfo.Curr_remote)
-	} else if len(remote_name) > 1 {
-		gitinfo.Curr_remote = remote_name
-	} else if len(gitinfo.Remotes) == 1 {
-		gitinfo.Curr_remote = gitinfo.Remotes[0]
+	// We need to change the cwd to the target path
+	cwd, _ := os.Getwd()
+	if strings.Compare(cwd, gitinfo.TargetPath) != 0 {
+		os.Chdir(gitinfo.TargetPath)
 	}
 
-	// Check that at least a name has been given
-	if len(gitinfo.Curr_remote) < 1 {
-		fmt.Println("A remote name must be choosen. Exiting ...")
-		os.Exit(1)
+	// Check for changes to be committed, unless not required, e.g., when
+	// amending the last commit might just change its message
+	if check_changes {
+		checkChangesToCommit(gitinfo)
 	}
 
-	// Check that the remote name is inside the list of all remotes
-	result := false
-	for _, remote := range gitinfo.Remotes {
-		if strings.Compare(gitinfo.Curr_remote, remote) == 0 {
-			result = true
-		}
-	}
+	fmt.Println("----------------------------------------------------------------------------")
 
-	if !result {
-		fmt.Println("A valid remote name must be given. Exiting ...")
-		os.Exit(1)
+	return gitinfo
+}
+
+// Returns the full message of the given commit
+func GetCommitMessage(commit string) (string, error) {
+	gitshow := exec.Command("git", "show", "-s", "--format=%B", commit)
+	var out bytes.Buffer
+	gitshow.Stdout = &out
+	gitshow.Stderr = os.Stderr
+	if err := gitshow.Run(); err != nil {
+		return "", err
 	}
 
-	// We need to change the cwd to the target path
-	cwd, _ := os.Getwd()
-	if strings.Compare(cwd, gitinfo.TargetPath) != 0 {
-		os.Chdir(gitinfo.TargetPath)
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Returns all the scopes used by the last commits of the current branch
+func GetKnownScopes(nof_commits int) []string {
+	// Take the subject of the last commits from git log
+	gitlog := exec.Command("git", "log", "--format=%s", fmt.Sprintf("-n%d", nof_commits))
+	var out bytes.Buffer
+	gitlog.Stdout = &out
+	if err := gitlog.Run(); err != nil {
+		return []string{}
 	}
 
-	// Check for changes to be committed
-	checkChangesToCommit(gitinfo)
+	// Extract the scope from each header of the form type(scope): ...
+	// where the type might be preceeded by an emoji
+	re := regexp.MustCompile(`^(?:\S+\s+)?[A-Za-z][\w-]*\(([^()]+)\)!?: `)
+	found := make(map[string]bool)
+	for _, line := range strings.Split(out.String(), "\n") {
+		matches := re.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
 
-	fmt.Println("----------------------------------------------------------------------------")
+		found[strings.TrimSpace(matches[1])] = true
+	}
 
-	return gitinfo
+	scopes := make([]string, 0, len(found))
+	for scope := range found {
+		scopes = append(scopes, scope)
+	}
+
+	sort.Strings(scopes)
+	return scopes
 }
 
-func (gi *GitInfo) FinalizeCommit(flag bool) {
+// Stages all the changes, commits and pushes them. Returns true if the
+// commit has been created, along with the error of the failed step, if any.
+func (gi *GitInfo) FinalizeCommit(flag bool) (bool, error) {
 	fmt.Println("[*] Previous changes needs to be staged before commiting.")
 
+	commit_cmd := "git commit -m ..."
+	if gi.Amend {
+		commit_cmd = "git commit --amend -m ..."
+	}
+
 	if !flag {
-		fmt.Println("[*] Running commands: <git add .> and <git commit -m ...> (Press ENTER to run, CTRL + C for exit)")
+		fmt.Printf("[*] Running commands: <git add .> and <%s> (Press ENTER to run, CTRL + C for exit)\n", commit_cmd)
 		fmt.Scanln()
 	} else {
-		fmt.Println("[*] Running commands: <git add .> and <git commit -m ...>")
+		fmt.Printf("[*] Running commands: <git add .> and <%s>\n", commit_cmd)
+	}
+
+	// The remote branches already containing the commit being amended
+	pushed := []string{}
+	if gi.Amend {
+		pushed, _ = GetRemoteBranches("HEAD")
 	}
 
 	// Run Git add command
@@ -356,41 +479,125 @@ func (gi *GitInfo) FinalizeCommit(flag bool) {
 	gitadd.Stdout = os.Stdout
 	err := gitadd.Run()
 	if err != nil {
-		fmt.Printf("An Error occurred: %s\n", err)
 		gi.RestorePreviousContent()
-		return
+		return false, err
 	}
 
-	// Run git commit
-	gitcommit := exec.Command("git", "commit", "-m", gi.Commit_str)
+	// Run git commit, replacing the last commit when amending
+	args := []string{"commit", "-m", gi.Commit_str}
+	if gi.Amend {
+		args = []string{"commit", "--amend", "-m", gi.Commit_str}
+	}
+
+	gitcommit := exec.Command("git", args...)
 	gitcommit.Stderr = os.Stderr
-	gitadd.Stdout = os.Stdout
+	gitcommit.Stdout = os.Stdout
 	err = gitcommit.Run()
 	if err != nil {
-		fmt.Printf("An Error occurred: %s\n", err)
 		gi.RestorePreviousContent()
-		return
+		return false, err
+	}
+
+	// Amending a commit already pushed requires a forced push, which is
+	// run only when the user agrees
+	if len(pushed) > 0 {
+		err = gi.pushAmended(flag, pushed)
+		gi.RestorePreviousContent()
+		return true, err
+	}
+
+	// Run git push
+	err = gi.Push(flag, gi.Curr_branch)
+	gi.RestorePreviousContent()
+	return true, err
+}
+
+// Selects the remote to push into, i.e., the given one or, if none, the one
+// chosen by the user when the repository has more than one. The default one
+// is taken when there is a single remote or when the user cannot be asked.
+func (gi *GitInfo) SelectRemote(remote_name string, ask bool) error {
+	gi.Curr_remote = remote_name
+	if len(remote_name) < 1 {
+		if ask && len(gi.Remotes) > 1 {
+			fmt.Print("\n[*] Please Choose a remote: ")
+			fmt.Scanln(&gi.Curr_remote)
+		} else {
+			gi.Curr_remote = gi.GetDefaultRemote()
+		}
+	}
+
+	// Check that at least a name has been given
+	if len(gi.Curr_remote) < 1 {
+		return fmt.Errorf("a remote name must be chosen")
+	}
+
+	// Check that the remote name is inside the list of all remotes
+	if !slices.Contains(gi.Remotes, gi.Curr_remote) {
+		return fmt.Errorf("%s is not a valid remote name", gi.Curr_remote)
+	}
+
+	return nil
+}
+
+// Returns the remote used when none is chosen, i.e., origin if configured,
+// otherwise the first one. Returns an empty string if there are no remotes.
+func (gi *GitInfo) GetDefaultRemote() string {
+	if slices.Contains(gi.Remotes, DEFAULT_REMOTE) {
+		return DEFAULT_REMOTE
+	}
+
+	if len(gi.Remotes) < 1 {
+		return ""
+	}
+
+	return gi.Remotes[0]
+}
+
+// Pushes the given refs into the current remote, setting the upstream of
+// the branches. Unless the flag is set, the user is asked to confirm.
+func (gi *GitInfo) Push(flag bool, refs ...string) error {
+	// The URLs the changes are pushed into, if known
+	urls := ""
+	if remote, ok := gi.Config.GetRemote(gi.Curr_remote); ok && len(remote.GetPushUrls()) > 0 {
+		urls = fmt.Sprintf(" (%s)", strings.Join(remote.GetPushUrls(), ", "))
 	}
 
 	if !flag {
-		fmt.Println("\n[*] Pushing changes into remote. (Press ENTER to run, CTRL + C for exit)")
+		fmt.Printf("\n[*] Pushing changes into remote %s%s. (Press ENTER to run, CTRL + C for exit)\n", gi.Curr_remote, urls)
 		fmt.Scanln()
 	} else {
-		fmt.Println("\n[*] Pushing changes into remote.")
+		fmt.Printf("\n[*] Pushing changes into remote %s%s.\n", gi.Curr_remote, urls)
 	}
 
-	// Run git push
-	gitpush := exec.Command("git", "push", "--set-upstream", gi.Curr_remote, gi.Curr_branch)
+	args := append([]string{"push", "--set-upstream", gi.Curr_remote}, refs...)
+	gitpush := exec.Command("git", args...)
 	gitpush.Stderr = os.Stderr
 	gitpush.Stdout = os.Stdout
-	err = gitpush.Run()
-	if err != nil {
-		fmt.Printf("An Error occurred: %s\n", err)
-		gi.RestorePreviousContent()
-		return
+	return gitpush.Run()
+}
+
+// Replaces the amended commit into the remote with a forced push, as long as
+// the remote branch has not changed since fetched. Unless the user agrees the
+// push is skipped, as it is always when the flag is set.
+func (gi *GitInfo) pushAmended(flag bool, pushed []string) error {
+	fmt.Printf("\n[*] The amended commit has already been pushed into %s\n", strings.Join(pushed, ", "))
+	if flag {
+		fmt.Println("[*] Skipping the push, run <git push --force-with-lease> to replace it")
+		return nil
 	}
 
-	gi.RestorePreviousContent()
+	answer := ""
+	fmt.Printf("[*] Replace it into remote %s with <git push --force-with-lease>? [y/N]: ", gi.Curr_remote)
+	fmt.Scanln(&answer)
+	if !strings.HasPrefix(strings.ToLower(answer), "y") {
+		fmt.Println("[*] Skipping the push")
+		return nil
+	}
+
+	gitpush := exec.Command("git", "push", "--force-with-lease", gi.Curr_remote, gi.Curr_branch)
+	gitpush.Stderr = os.Stderr
+	gitpush.Stdout = os.Stdout
+	return gitpush.Run()
 }
 
 func (gi *GitInfo) RestorePreviousContent() {
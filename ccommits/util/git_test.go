@@ -0,0 +1,26 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTrustRepository(t *testing.T) {
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "user.name")
+	t.Setenv("GIT_CONFIG_VALUE_0", "Jane")
+	t.Setenv("GIT_CONFIG_KEY_1", "")
+	t.Setenv("GIT_CONFIG_VALUE_1", "")
+
+	// Each repository is added once, after the settings already given
+	trustRepository("/repo")
+	trustRepository("/repo")
+	if count := os.Getenv("GIT_CONFIG_COUNT"); count != "2" {
+		t.Errorf("GIT_CONFIG_COUNT = %s, want 2", count)
+	}
+
+	key, value := os.Getenv("GIT_CONFIG_KEY_1"), os.Getenv("GIT_CONFIG_VALUE_1")
+	if key != "safe.directory" || value != "/repo" {
+		t.Errorf("GIT_CONFIG_KEY_1 = %s, GIT_CONFIG_VALUE_1 = %s, want safe.directory, /repo", key, value)
+	}
+}
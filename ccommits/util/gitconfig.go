@@ -0,0 +1,118 @@
+package util
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// The name of the remote preferred when more than one is configured
+const DEFAULT_REMOTE string = "origin"
+
+// The git configuration merged from all its sources, i.e., system, global,
+// local and worktree, with includes already resolved by git
+type GitConfig struct {
+	keys   []string            // All the keys, in order of first appearance
+	values map[string][]string // All the values of each key, in order
+}
+
+type Remote struct {
+	Name     string   // The name of the remote
+	Urls     []string // The URLs used to fetch
+	PushUrls []string // The URLs used to push (Optional)
+}
+
+// Reads the configuration of the repository at the given path. Sections and
+// variables of the keys are lower-case, while subsections are kept as they are.
+func ReadGitConfig(rootpath string) (*GitConfig, error) {
+	gitconfig := exec.Command("git", "config", "--list", "-z")
+	gitconfig.Dir = rootpath
+	var out bytes.Buffer
+	gitconfig.Stdout = &out
+	gitconfig.Stderr = os.Stderr
+	if err := gitconfig.Run(); err != nil {
+		return nil, err
+	}
+
+	return parseGitConfig(out.String()), nil
+}
+
+// Parses the output of git config --list -z. Each entry is the key followed
+// by a new line and the value, which might span multiple lines. Keys without
+// a value have no new line.
+func parseGitConfig(out string) *GitConfig {
+	config := &GitConfig{make([]string, 0), make(map[string][]string)}
+	for _, entry := range strings.Split(out, "\x00") {
+		if len(entry) < 1 {
+			continue
+		}
+
+		key, value, _ := strings.Cut(entry, "\n")
+		if _, ok := config.values[key]; !ok {
+			config.keys = append(config.keys, key)
+		}
+
+		config.values[key] = append(config.values[key], value)
+	}
+
+	return config
+}
+
+// Returns the last value of the given key, which takes precedence
+func (gc *GitConfig) Get(key string) (string, bool) {
+	values := gc.values[key]
+	if len(values) < 1 {
+		return "", false
+	}
+
+	return values[len(values)-1], true
+}
+
+// Returns all the values of the given key, e.g., of multiple URLs
+func (gc *GitConfig) GetAll(key string) []string {
+	return gc.values[key]
+}
+
+// Returns all the remotes, in order of appearance. Names might contain dots,
+// hence the name of a remote is all between the section and the variable.
+func (gc *GitConfig) GetRemotes() []Remote {
+	remotes := make([]Remote, 0)
+	found := make(map[string]bool)
+	for _, key := range gc.keys {
+		if !strings.HasPrefix(key, "remote.") || strings.Count(key, ".") < 2 {
+			continue
+		}
+
+		name := key[len("remote."):strings.LastIndex(key, ".")]
+		if found[name] {
+			continue
+		}
+
+		found[name] = true
+		remotes = append(remotes, Remote{name, gc.GetAll("remote." + name + ".url"),
+			gc.GetAll("remote." + name + ".pushurl")})
+	}
+
+	return remotes
+}
+
+// Returns the remote with the given name
+func (gc *GitConfig) GetRemote(name string) (Remote, bool) {
+	for _, remote := range gc.GetRemotes() {
+		if remote.Name == name {
+			return remote, true
+		}
+	}
+
+	return Remote{}, false
+}
+
+// Returns the URLs used to push, which default to the fetch ones
+func (r Remote) GetPushUrls() []string {
+	if len(r.PushUrls) > 0 {
+		return r.PushUrls
+	}
+
+	return r.Urls
+}
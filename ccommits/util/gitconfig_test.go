@@ -0,0 +1,114 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Returns the output of git config --list -z with the given entries
+func listOutput(entries ...string) string {
+	return strings.Join(entries, "\x00") + "\x00"
+}
+
+func TestParseGitConfig(t *testing.T) {
+	tests := []struct {
+		out    string
+		key    string
+		value  string
+		found  bool
+		values []string
+	}{
+		{"", "user.name", "", false, nil},
+		{listOutput("user.name\nJane Doe"), "user.name", "Jane Doe", true, []string{"Jane Doe"}},
+		// Later values take precedence, while all of them are kept
+		{listOutput("user.name\nJane", "core.editor\nvim", "user.name\nJohn"), "user.name", "John", true,
+			[]string{"Jane", "John"}},
+		// Values can span multiple lines and contain the separators of other formats
+		{listOutput("alias.lg\nlog\n--graph = x"), "alias.lg", "log\n--graph = x", true,
+			[]string{"log\n--graph = x"}},
+		// Keys without a value are true booleans, e.g., [core] bare
+		{listOutput("core.bare"), "core.bare", "", true, []string{""}},
+		{listOutput("user.email\n"), "user.email", "", true, []string{""}},
+	}
+
+	for idx, test := range tests {
+		config := parseGitConfig(test.out)
+		value, found := config.Get(test.key)
+		if value != test.value || found != test.found {
+			t.Errorf("test %d: Get(%q) = %q, %t, want %q, %t", idx, test.key, value, found, test.value, test.found)
+		}
+
+		if values := config.GetAll(test.key); !reflect.DeepEqual(values, test.values) {
+			t.Errorf("test %d: GetAll(%q) = %q, want %q", idx, test.key, values, test.values)
+		}
+	}
+}
+
+func TestGetRemotes(t *testing.T) {
+	tests := []struct {
+		out  string
+		want []Remote
+	}{
+		{listOutput("user.name\nJane"), []Remote{}},
+		{listOutput("remote.origin.url\ngit@github.com:a/b.git", "remote.origin.fetch\n+refs/heads/*:refs/remotes/origin/*"),
+			[]Remote{{"origin", []string{"git@github.com:a/b.git"}, nil}}},
+		// Remotes are listed in order of appearance, also when their keys are interleaved
+		{listOutput("remote.upstream.url\nu1", "remote.origin.url\no1", "remote.upstream.pushurl\np1",
+			"remote.upstream.pushurl\np2"),
+			[]Remote{{"upstream", []string{"u1"}, []string{"p1", "p2"}}, {"origin", []string{"o1"}, nil}}},
+		// Names of the remotes might contain dots
+		{listOutput("remote.my.fork.url\nf1", "remote.a.b.c.pushurl\np1"),
+			[]Remote{{"my.fork", []string{"f1"}, nil}, {"a.b.c", nil, []string{"p1"}}}},
+		// Keys without a variable do not define a remote
+		{listOutput("remote.origin", "remotes.origin.url\nx"), []Remote{}},
+	}
+
+	for idx, test := range tests {
+		if remotes := parseGitConfig(test.out).GetRemotes(); !reflect.DeepEqual(remotes, test.want) {
+			t.Errorf("test %d: GetRemotes() = %+v, want %+v", idx, remotes, test.want)
+		}
+	}
+}
+
+func TestGetPushUrls(t *testing.T) {
+	config := parseGitConfig(listOutput("remote.origin.url\nfetch", "remote.fork.url\nfetch",
+		"remote.fork.pushurl\npush1", "remote.fork.pushurl\npush2"))
+
+	tests := map[string][]string{"origin": {"fetch"}, "fork": {"push1", "push2"}}
+	for name, want := range tests {
+		remote, ok := config.GetRemote(name)
+		if !ok {
+			t.Errorf("GetRemote(%q) not found", name)
+			continue
+		}
+
+		if urls := remote.GetPushUrls(); !reflect.DeepEqual(urls, want) {
+			t.Errorf("GetPushUrls() of %s = %v, want %v", name, urls, want)
+		}
+	}
+
+	if _, ok := config.GetRemote("upstream"); ok {
+		t.Errorf("GetRemote(\"upstream\") found, want none")
+	}
+}
+
+func TestGetRepositoryName(t *testing.T) {
+	tests := []struct {
+		remotes []Remote
+		want    string
+	}{
+		{[]Remote{}, "folder"},
+		{[]Remote{{"origin", nil, []string{"p"}}}, "folder"},
+		{[]Remote{{"origin", []string{"https://github.com/user/repo.git"}, nil}}, "user/repo"},
+		{[]Remote{{"fork", []string{"git@github.com:me/repo.git"}, nil},
+			{"origin", []string{"git@github.com:user/repo.git"}, nil}}, "user/repo"},
+		{[]Remote{{"local", []string{"/srv/git/repo.git"}, nil}}, "git/repo"},
+	}
+
+	for _, test := range tests {
+		if name := getRepositoryName("/home/me/folder", test.remotes); name != test.want {
+			t.Errorf("getRepositoryName(%+v) = %q, want %q", test.remotes, name, test.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Separators of the fields and of the commits in the output of git log
+const FIELD_SEPARATOR string = "\x1f"
+const COMMIT_SEPARATOR string = "\x1e"
+
+type Commit struct {
+	Sha     string // The full hash of the commit
+	Date    string // The date of the commit, as YYYY-MM-DD
+	Message string // The full message of the commit
+}
+
+// Returns the abbreviated hash of the commit
+func (c Commit) GetShortSha() string {
+	return c.Sha[:min(len(c.Sha), 7)]
+}
+
+// Returns all the commits, merges excluded, in the given revision range,
+// e.g., v1.0.0..HEAD, from the most recent to the oldest
+func GetCommits(revision_range string) ([]Commit, error) {
+	return getCommits(revision_range)
+}
+
+// Returns the last commits, merges excluded, reachable from the given one
+func GetLastCommits(commit string, nof_commits int) ([]Commit, error) {
+	return getCommits(fmt.Sprintf("-n%d", nof_commits), commit)
+}
+
+// Runs git log with the given arguments and returns the listed commits
+func getCommits(args ...string) ([]Commit, error) {
+	format := fmt.Sprintf("--format=%%H%s%%as%s%%B%s", FIELD_SEPARATOR, FIELD_SEPARATOR, COMMIT_SEPARATOR)
+	args = append(append([]string{"log", "--no-merges", format}, args...), "--")
+	gitlog := exec.Command("git", args...)
+	var out bytes.Buffer
+	gitlog.Stdout = &out
+	gitlog.Stderr = os.Stderr
+	if err := gitlog.Run(); err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, 0)
+	for _, record := range strings.Split(out.String(), COMMIT_SEPARATOR) {
+		fields := strings.SplitN(strings.TrimSpace(record), FIELD_SEPARATOR, 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		commits = append(commits, Commit{fields[0], fields[1], strings.TrimSpace(fields[2])})
+	}
+
+	return commits, nil
+}
+
+// Returns all the tags reachable from the given commit
+func GetTags(commit string) ([]string, error) {
+	gittag := exec.Command("git", "tag", "--merged", commit)
+	var out bytes.Buffer
+	gittag.Stdout = &out
+	gittag.Stderr = os.Stderr
+	if err := gittag.Run(); err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(out.String()), nil
+}
+
+// Commits the given files only, regardless of the other staged changes
+func CommitFiles(commit_str string, files ...string) error {
+	gitadd := exec.Command("git", append([]string{"add", "--"}, files...)...)
+	gitadd.Stderr = os.Stderr
+	if err := gitadd.Run(); err != nil {
+		return err
+	}
+
+	args := append([]string{"commit", "-m", commit_str, "--"}, files...)
+	gitcommit := exec.Command("git", args...)
+	gitcommit.Stdout = os.Stdout
+	gitcommit.Stderr = os.Stderr
+	return gitcommit.Run()
+}
+
+// Creates an annotated tag on HEAD. The message is kept as it is, since
+// lines starting with # would otherwise be removed.
+func CreateTag(tag, tag_str string) error {
+	gittag := exec.Command("git", "tag", "-a", "--cleanup=whitespace", "-m", tag_str, tag)
+	gittag.Stdout = os.Stdout
+	gittag.Stderr = os.Stderr
+	return gittag.Run()
+}
+
+// Returns the full hash of the given commit, failing if there is no such commit
+func ResolveCommit(commit string) (string, error) {
+	gitrevparse := exec.Command("git", "rev-parse", "--verify", "--quiet", commit+"^{commit}")
+	var out bytes.Buffer
+	gitrevparse.Stdout = &out
+	if err := gitrevparse.Run(); err != nil {
+		return "", fmt.Errorf("unknown commit %s", commit)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Returns true if the first commit is an ancestor of the second one
+func IsAncestor(ancestor, commit string) bool {
+	return exec.Command("git", "merge-base", "--is-ancestor", ancestor, commit).Run() == nil
+}
+
+// Returns true if the commit has no parents, i.e., it is the first one
+func IsRootCommit(commit string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", commit+"^").Run() != nil
+}
+
+// Returns the remote branches containing the given commit
+func GetRemoteBranches(commit string) ([]string, error) {
+	gitbranch := exec.Command("git", "branch", "--remotes", "--format=%(refname:short)", "--contains", commit)
+	var out bytes.Buffer
+	gitbranch.Stdout = &out
+	gitbranch.Stderr = os.Stderr
+	if err := gitbranch.Run(); err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(out.String()), nil
+}
+
+// Runs an interactive rebase of all the commits after the given one, or of
+// the whole history if empty. The editors used by git must be set through
+// the given environment, e.g., GIT_SEQUENCE_EDITOR.
+func Rebase(base string, env []string) error {
+	args := []string{"rebase", "--interactive", "--autostash", "--rebase-merges"}
+	if len(base) < 1 {
+		args = append(args, "--root")
+	} else {
+		args = append(args, base)
+	}
+
+	gitrebase := exec.Command("git", args...)
+	gitrebase.Env = append(os.Environ(), env...)
+	gitrebase.Stdout = os.Stdout
+	gitrebase.Stderr = os.Stderr
+	return gitrebase.Run()
+}
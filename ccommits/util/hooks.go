@@ -0,0 +1,187 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The marker identifying the hooks written by ccommits
+const HOOK_MARKER string = "# Managed by ccommits"
+
+// Suffix of the previously existing hooks, which are chained by ccommits
+const CHAINED_SUFFIX string = ".ccommits-chained"
+
+// All the hooks managed by ccommits
+var HOOKS = []string{"commit-msg", "prepare-commit-msg", "pre-push"}
+
+// The hook script. The previously existing hook, if any, is run first and,
+// only when it succeeds, ccommits is run. The pre-push hook receives the
+// refs to push on the standard input, hence it is read once and fed to both.
+const HOOK_TEMPLATE string = `#!/bin/sh
+%s: run 'ccommits hooks uninstall' to remove it
+ccommits=%s
+chained="$(dirname "$0")/%s%s"
+input=""
+if [ "%s" = "pre-push" ]; then
+	input=$(cat)
+fi
+
+feed() {
+	if [ -n "$input" ]; then
+		printf '%%s\n' "$input"
+	fi
+}
+
+if [ -x "$chained" ]; then
+	feed | "$chained" "$@" || exit $?
+fi
+
+if [ ! -x "$ccommits" ]; then
+	echo "ccommits not found at $ccommits, skipping the %s hook" >&2
+	exit 0
+fi
+
+feed | "$ccommits" hooks run %s "$@"
+`
+
+// Quotes the string so that it can be safely used in a shell script
+func shellQuote(content string) string {
+	return "'" + strings.ReplaceAll(content, "'", `'"'"'`) + "'"
+}
+
+// Returns true if the given file is a hook written by ccommits
+func isManagedHook(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && bytes.Contains(data, []byte(HOOK_MARKER))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Returns the folder containing the hooks of the repository. It is the one
+// configured with core.hooksPath or the hooks folder of the shared git folder.
+func (gi *GitInfo) GetHooksDir() string {
+	gitconfig := exec.Command("git", "config", "--get", "core.hooksPath")
+	gitconfig.Dir = gi.TargetPath
+	var out bytes.Buffer
+	gitconfig.Stdout = &out
+	if err := gitconfig.Run(); err != nil || len(strings.TrimSpace(out.String())) < 1 {
+		return filepath.Join(gi.CommonDir, "hooks")
+	}
+
+	hooks_path := strings.TrimSpace(out.String())
+	if strings.HasPrefix(hooks_path, "~/") {
+		home, _ := os.UserHomeDir()
+		hooks_path = filepath.Join(home, hooks_path[2:])
+	}
+
+	// Relative paths are relative to the root of the working tree
+	if !filepath.IsAbs(hooks_path) {
+		hooks_path = filepath.Join(gi.TargetPath, hooks_path)
+	}
+
+	return hooks_path
+}
+
+// Writes all the ccommits hooks into the hooks folder. The executable is
+// the path of ccommits run by the hooks. Hooks already existing are not
+// overwritten but renamed and chained, i.e., run before ccommits.
+func InstallHooks(hooks_dir, executable string) error {
+	if err := os.MkdirAll(hooks_dir, 0755); err != nil {
+		return err
+	}
+
+	for _, hook := range HOOKS {
+		hook_path := filepath.Join(hooks_dir, hook)
+		chained_path := hook_path + CHAINED_SUFFIX
+
+		// A previously existing hook, not written by ccommits, is chained
+		if fileExists(hook_path) && !isManagedHook(hook_path) {
+			if fileExists(chained_path) {
+				return fmt.Errorf("cannot chain %s, %s already exists", hook_path, chained_path)
+			}
+
+			if err := os.Rename(hook_path, chained_path); err != nil {
+				return err
+			}
+		}
+
+		content := fmt.Sprintf(HOOK_TEMPLATE, HOOK_MARKER, shellQuote(executable),
+			hook, CHAINED_SUFFIX, hook, hook, hook)
+		if err := os.WriteFile(hook_path, []byte(content), 0755); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Removes all the ccommits hooks from the hooks folder, restoring the
+// previously existing ones that were chained
+func UninstallHooks(hooks_dir string) error {
+	for _, hook := range HOOKS {
+		hook_path := filepath.Join(hooks_dir, hook)
+		chained_path := hook_path + CHAINED_SUFFIX
+
+		if !isManagedHook(hook_path) {
+			continue
+		}
+
+		if err := os.Remove(hook_path); err != nil {
+			return err
+		}
+
+		if fileExists(chained_path) {
+			if err := os.Rename(chained_path, hook_path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns a description of the status of the given hook
+func GetHookStatus(hooks_dir, hook string) string {
+	hook_path := filepath.Join(hooks_dir, hook)
+	if !fileExists(hook_path) {
+		return "not installed"
+	}
+
+	if !isManagedHook(hook_path) {
+		return "not installed, a different hook exists"
+	}
+
+	if fileExists(hook_path + CHAINED_SUFFIX) {
+		return "installed, chaining the previous hook"
+	}
+
+	return "installed"
+}
+
+// Returns the commits that are going to be pushed, given the local and
+// remote commit of the ref as received by the pre-push hook
+func GetCommitsToPush(remote, local_sha, remote_sha string) ([]string, error) {
+	// When the ref does not exist on the remote, all the commits that
+	// are not in any other ref of the remote are pushed
+	args := []string{"rev-list", "--no-merges", local_sha, "--not", "--remotes=" + remote}
+	if strings.Trim(remote_sha, "0") != "" {
+		args = []string{"rev-list", "--no-merges", remote_sha + ".." + local_sha}
+	}
+
+	gitrevlist := exec.Command("git", args...)
+	var out bytes.Buffer
+	gitrevlist.Stdout = &out
+	gitrevlist.Stderr = os.Stderr
+	if err := gitrevlist.Run(); err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(out.String()), nil
+}
@@ -1,39 +1,322 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/lmriccardo/conventional-commits-cli/ccommits"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/commands"
+	"github.com/lmriccardo/conventional-commits-cli/ccommits/message"
 	"github.com/lmriccardo/conventional-commits-cli/ccommits/util"
 )
 
-func main() {
-	fmt.Println(ccommits.TITLE)
-	fmt.Printf("Running Version: %s\n", ccommits.VERSION)
-	fmt.Println("GitHub Repository: https://github.com/lmriccardo/conventional-commits-cli.git")
+// The flags writing the message without the composer
+var MESSAGE_FLAGS = []string{"type", "scope", "emoji", "subject", "body", "trailer", "breaking"}
+
+// The repeatable -trailer flag, each one written as "Key: value"
+type trailerFlag []string
+
+func (tf *trailerFlag) String() string {
+	return strings.Join(*tf, ", ")
+}
+
+func (tf *trailerFlag) Set(value string) error {
+	*tf = append(*tf, value)
+	return nil
+}
+
+// The fields of the message written with -json. Unlike the message, only
+// the content is kept, not how it is formatted.
+type jsonTrailer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type jsonMessage struct {
+	Type     string        `json:"type"`
+	Scope    string        `json:"scope"`
+	Breaking bool          `json:"breaking"`
+	Emoji    string        `json:"emoji"`
+	Subject  string        `json:"subject"`
+	Body     string        `json:"body"`
+	Trailers []jsonTrailer `json:"trailers"`
+}
+
+// Prints the formatted commit message, then commits and pushes it. Returns
+// if the commit has been created and if all the steps succeeded, i.e., the
+// commit might have been created while pushing failed.
+func finalizeCommit(gitinfo *util.GitInfo, fmt_commit string, yes_flag bool) (bool, bool) {
+	fmt.Println("[*] Following result obtained")
+	fmt.Println()
+	fmt.Printf("%s\n\n", fmt_commit)
 	fmt.Println()
 
+	fmt.Println("------------------------- FINALIZING THE COMMIT ----------------------------")
+
+	fmt.Println("[*] Finalizing the Commit and Closing")
+	gitinfo.Commit_str = fmt_commit
+	committed, err := gitinfo.FinalizeCommit(yes_flag)
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+	}
+
+	fmt.Println("----------------------------------------------------------------------------")
+	return committed, err == nil
+}
+
+// Writes the formatted message, or its fields as JSON, into the given file
+// or, if none, into the standard output
+func writeMessage(fmt_commit string, json_flag bool, output string, stdout io.Writer) error {
+	content := fmt_commit + "\n"
+	if json_flag {
+		msg, err := message.Parse(fmt_commit)
+		if err != nil {
+			return err
+		}
+
+		fields := jsonMessage{msg.Type, msg.Scope, msg.Breaking, msg.Emoji, msg.Subject, msg.Body,
+			make([]jsonTrailer, 0, len(msg.Trailers))}
+		for _, trailer := range msg.Trailers {
+			fields.Trailers = append(fields.Trailers, jsonTrailer{trailer.Key, trailer.GetValue()})
+		}
+
+		data, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		content = string(data) + "\n"
+	}
+
+	if len(output) < 1 || output == "-" {
+		_, err := io.WriteString(stdout, content)
+		return err
+	}
+
+	return os.WriteFile(output, []byte(content), 0644)
+}
+
+func main() {
+	// Sub-commands, e.g., ccommits lint, are run instead of the composer
+	if len(os.Args) > 1 {
+		if command, ok := commands.COMMANDS[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:]))
+		}
+	}
+
 	// Define the expected input command line argument
 	remote_name := flag.String("remote", "", "The chosen remote name")
 	yes_flag := flag.Bool("yes", false, "Skip all user input pauses when finalizing commit")
+	amend_flag := flag.Bool("amend", false, "Load the last commit message and amend the last commit")
+	template := flag.String("template", "", "Fill the composer with the template of the given name")
+	print_flag := flag.Bool("print", false, "Print the message instead of committing it")
+	json_flag := flag.Bool("json", false, "Print the fields of the message as JSON instead of committing it")
+	output := flag.String("output", "", "Write the message into the given file instead of committing it")
+
+	// The message can be given with flags instead of the composer, e.g., by bots
+	change_type := flag.String("type", "", "The type of the change (skips the composer)")
+	scope := flag.String("scope", "", "The scope of the change (skips the composer)")
+	emoji := flag.String("emoji", "", "The gitmoji, either unicode or shortcode (skips the composer)")
+	subject := flag.String("subject", "", "The short description (skips the composer)")
+	body := flag.String("body", "", "The longer description (skips the composer)")
+	breaking := flag.Bool("breaking", false, "Mark the change as breaking (skips the composer)")
+	var trailers trailerFlag
+	flag.Var(&trailers, "trailer", "A footer trailer written as \"Key: value\", can be repeated (skips the composer)")
 	flag.Parse()
 
+	non_interactive := false
+	flag.Visit(func(f *flag.Flag) {
+		non_interactive = non_interactive || slices.Contains(MESSAGE_FLAGS, f.Name)
+	})
+
+	// The message given with flags never waits for user input
+	if non_interactive {
+		*yes_flag = true
+	}
+
 	cwd, _ := os.Getwd()
 
+	// Printing the message replaces the commit. Everything else is written
+	// to the standard error, so that the standard output holds the message.
+	// The output file is relative to the current folder, which changes later.
+	print_mode := *print_flag || *json_flag || len(*output) > 0
+	stdout := os.Stdout
+	if print_mode {
+		os.Stdout = os.Stderr
+	}
+
+	if len(*output) > 0 && *output != "-" && !filepath.IsAbs(*output) {
+		*output = filepath.Join(cwd, *output)
+	}
+
+	fmt.Println(ccommits.TITLE)
+	fmt.Printf("Running Version: %s\n", ccommits.VERSION)
+	fmt.Println("GitHub Repository: https://github.com/lmriccardo/conventional-commits-cli.git")
+	fmt.Println()
+
 	// Before getting git repository info it must check if the current environment
 	// is a docker container by using the defined heuristics
 	target_folder, src_folder, entry_path := util.PerformContainerChecks(cwd)
 
 	// Gets repository information
-	gitinfo := util.GetGitRepositoryInformation(*remote_name, target_folder, src_folder, entry_path)
+	// Nothing is committed when printing, while amending might just change
+	// the message, hence there might be no changes to commit
+	check_changes := !*amend_flag && !print_mode
+	gitinfo := util.GetGitRepositoryInformation(target_folder, src_folder, entry_path, check_changes)
+	gitinfo.Amend = *amend_flag
+	if print_mode {
+		// The remote is only displayed, since nothing is pushed
+		gitinfo.Curr_remote = *remote_name
+		if len(*remote_name) < 1 {
+			gitinfo.Curr_remote = gitinfo.GetDefaultRemote()
+		}
+	} else if err := gitinfo.SelectRemote(*remote_name, !*yes_flag); err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		gitinfo.RestorePreviousContent()
+		os.Exit(1)
+	}
+
+	// Loads the configuration of the repository, if any
+	config, err := ccommits.LoadConfig(gitinfo.TargetPath)
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+		gitinfo.RestorePreviousContent()
+		os.Exit(1)
+	}
+
+	// The message given with flags goes through the same formatting and
+	// linting of the composer, then it is committed right away. With
+	// -amend it replaces the message of the last commit.
+	if non_interactive {
+		if len(*change_type) < 1 || len(*subject) < 1 {
+			fmt.Println("An Error occurred: both -type and -subject must be given")
+			gitinfo.RestorePreviousContent()
+			os.Exit(1)
+		}
+
+		if len(*template) > 0 {
+			fmt.Println("An Error occurred: -template cannot be used along with the message flags")
+			gitinfo.RestorePreviousContent()
+			os.Exit(1)
+		}
+
+		msg := &message.Message{Type: *change_type, Scope: *scope, Breaking: *breaking,
+			Emoji: *emoji, Subject: *subject, Body: *body}
+		for _, trailer := range trailers {
+			key, value, ok := strings.Cut(trailer, ":")
+			if !ok {
+				fmt.Printf("An Error occurred: trailer %q must be written as \"Key: value\"\n", trailer)
+				gitinfo.RestorePreviousContent()
+				os.Exit(1)
+			}
+
+			msg.AddTrailer(strings.TrimSpace(key), strings.TrimSpace(value))
+		}
+
+		fmt_commit, violations, err := config.FormatMessage(msg)
+		if err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+			gitinfo.RestorePreviousContent()
+			os.Exit(1)
+		}
+
+		if commands.ReportViolations("flags", violations) {
+			gitinfo.RestorePreviousContent()
+			os.Exit(1)
+		}
+
+		if !print_mode {
+			if _, ok := finalizeCommit(gitinfo, fmt_commit, *yes_flag); !ok {
+				os.Exit(1)
+			}
+
+			return
+		}
+
+		gitinfo.RestorePreviousContent()
+		if err := writeMessage(fmt_commit, *json_flag, *output, stdout); err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	// When amending, the boxes start with the message of the last commit
+	var amended *message.Message
+	if *amend_flag {
+		amended, err = ccommits.LoadCommitMessage("HEAD")
+		if err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+			gitinfo.RestorePreviousContent()
+			os.Exit(1)
+		}
+	}
+
+	if _, ok := config.Templates[*template]; len(*template) > 0 && !ok {
+		fmt.Printf("An Error occurred: unknown template %q\n", *template)
+		gitinfo.RestorePreviousContent()
+		os.Exit(1)
+	}
+
+	// The draft left by a previous run, e.g., closed before committing,
+	// can be restored, since -yes only skips the pauses when finalizing.
+	// Drafts are messages of new commits, hence when amending they are
+	// neither restored nor replaced.
+	draft_path := ccommits.GetDraftPath(gitinfo)
+	draft, err := ccommits.LoadDraft(draft_path)
+	if err != nil {
+		fmt.Printf("An Error occurred: %s\n", err)
+	}
+
+	if amended != nil {
+		if draft != nil {
+			fmt.Println("\n[*] A draft of a previous message has been found, it is kept for the next commit")
+		}
+
+		draft, draft_path = nil, ""
+	}
+
+	if draft != nil {
+		answer := ""
+		fmt.Print("\n[*] A draft of a previous message has been found, restore it? [Y/n]: ")
+		fmt.Scanln(&answer)
+		if strings.HasPrefix(strings.ToLower(answer), "n") {
+			draft = nil
+		}
+	}
 
 	fmt.Println("\n[*] Running conventional commits cli app")
 	time.Sleep(time.Second)
 
-	app := ccommits.CCommitWindow_new(gitinfo)
+	app := ccommits.CCommitWindow_new(gitinfo, config)
+	app.SetDraft(draft_path)
+	if amended != nil {
+		// The last commit is not amended if its message would silently change
+		app.Prefill(amended)
+		if err := app.CheckPrefill(amended); err != nil {
+			app.Close()
+			fmt.Printf("An Error occurred: %s, amend it with git commit --amend\n", err)
+			gitinfo.RestorePreviousContent()
+			os.Exit(1)
+		}
+	}
+
+	if draft != nil {
+		app.Prefill(draft)
+	}
+
+	if len(*template) > 0 {
+		app.ApplyTemplate(*template)
+	}
+
 	fmt_commit := app.Run()
 	if len(fmt_commit) < 1 {
 		fmt.Println("Invalid formatted conventional commit. Exiting ...")
@@ -41,16 +324,26 @@ func main() {
 		os.Exit(1)
 	}
 
-	fmt.Println("[*] Following result obtained")
-	fmt.Println()
-	fmt.Printf("%s\n\n", fmt_commit)
-	fmt.Println()
-
-	fmt.Println("------------------------- FINALIZING THE COMMIT ----------------------------")
+	// The draft is not needed anymore once printed or committed
+	committed, ok := true, true
+	if print_mode {
+		err = writeMessage(fmt_commit, *json_flag, *output, stdout)
+		gitinfo.RestorePreviousContent()
+		if err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+			os.Exit(1)
+		}
+	} else {
+		committed, ok = finalizeCommit(gitinfo, fmt_commit, *yes_flag)
+	}
 
-	fmt.Println("[*] Finalizing the Commit and Closing")
-	gitinfo.Commit_str = fmt_commit
-	gitinfo.FinalizeCommit(*yes_flag)
+	if committed && len(draft_path) > 0 {
+		if err := ccommits.RemoveDraft(draft_path); err != nil {
+			fmt.Printf("An Error occurred: %s\n", err)
+		}
+	}
 
-	fmt.Println("----------------------------------------------------------------------------")
+	if !ok {
+		os.Exit(1)
+	}
 }